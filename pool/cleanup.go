@@ -1,6 +1,8 @@
 package pool
 
 import (
+	"cmp"
+	"slices"
 	"time"
 )
 
@@ -28,9 +30,11 @@ func (p *ShardedPool[T, P]) cleanup() {
 		return
 	}
 
-	for _, shard := range p.Shards {
+	start := time.Now()
+	for _, shard := range p.shardList() {
 		p.cleanupShard(shard)
 	}
+	p.cleanupStats.record(time.Since(start))
 }
 
 func (p *ShardedPool[T, P]) cleanupShard(shard *Shard[T, P]) {
@@ -39,71 +43,124 @@ func (p *ShardedPool[T, P]) cleanupShard(shard *Shard[T, P]) {
 		return
 	}
 
-	keptHead, keptTail, evictedCount := p.filterUsableObjects(oldHead)
+	keptHead, keptTail, evictedCount := p.filterUsableObjects(oldHead, p.idleFloor(), p.shardQuota(shard))
 
 	if evictedCount > 0 {
+		shard.idle.Add(-int64(evictedCount))
 		p.CurrentPoolLength.Add(-int64(evictedCount))
+		if c := shard.counters; c != nil {
+			c.evictions.Add(int64(evictedCount))
+		}
+		for range evictedCount {
+			p.replenish(shard.index)
+		}
 	}
 
 	if keptHead != nil {
 		p.reinsertKeptObjects(shard, keptHead, keptTail)
+		p.settle(shard)
 	}
 }
 
+// tryTakeOwnership detaches every idle object in the shard, the Single slot first and
+// then the Head list, as one chain for filtering; nil if the shard is empty. The
+// shard's idle counter keeps counting the whole chain until the caller adjusts it.
 func (p *ShardedPool[T, P]) tryTakeOwnership(shard *Shard[T, P]) P {
-	head := P(shard.Head.Load())
-	if head == nil {
-		return nil
-	}
-	if !shard.Head.CompareAndSwap(head, nil) {
-		return nil
+	head := shard.takeAll()
+	if single := P(shard.Single.Swap(nil)); single != nil {
+		shard.idle.Add(1)
+		single.SetNext(head)
+		head = single
 	}
 	return head
 }
 
-// filterUsableObjects filters objects based on usage count and returns the kept head, kept tail, and number of evicted objects.
-func (p *ShardedPool[T, P]) filterUsableObjects(head P) (keptHead, keptTail P, evictedCount int) {
-	current := head
+// noLimit tells filterUsableObjects not to cap how many objects a shard keeps.
+const noLimit = -1
 
-	for current != nil {
+// idleFloor is how many idle objects cleanup leaves in each live shard: the larger of
+// MinIdlePerShard and MinIdle spread evenly (rounded up) over the shards.
+func (p *ShardedPool[T, P]) idleFloor() int {
+	numShards := len(p.shardList())
+	return max(p.cfg.Cleanup.MinIdlePerShard, (p.cfg.Cleanup.MinIdle+numShards-1)/numShards)
+}
+
+// shardQuota is shard's share of TargetSize: an even split, with the remainder going
+// one each to the lowest-indexed shards. noLimit when TargetSize is unset.
+func (p *ShardedPool[T, P]) shardQuota(shard *Shard[T, P]) int {
+	target := p.cfg.Cleanup.TargetSize
+	if target <= 0 {
+		return noLimit
+	}
+	numShards := len(p.shardList())
+	quota := target / numShards
+	if shard.index < target%numShards {
+		quota++
+	}
+	return quota
+}
+
+// idleTooLong reports whether obj has been idle longer than MaxIdleTime at now.
+func (p *ShardedPool[T, P]) idleTooLong(obj P, now int64) bool {
+	maxIdle := p.cfg.Cleanup.MaxIdleTime
+	return maxIdle > 0 && now-p.meta(obj).lastUsed.Load() > int64(maxIdle)
+}
+
+// filterUsableObjects filters objects based on usage count and idle time and returns the kept head, kept tail, and number of evicted objects.
+// Objects past MaxLifetime are always evicted.
+// At most maxKeep objects are kept (noLimit for no cap) and, if fewer than minKeep pass,
+// the most-used of the rest are kept as well; minKeep wins over maxKeep.
+func (p *ShardedPool[T, P]) filterUsableObjects(head P, minKeep, maxKeep int) (keptHead, keptTail P, evictedCount int) {
+	now := time.Now().UnixNano()
+	var passed, failed []P
+	for current := head; current != nil; {
 		next := current.GetNext()
-		usageCount := current.GetUsageCount()
-
-		if usageCount >= p.cfg.Cleanup.MinUsageCount {
-			current.ResetUsage()
-			if keptHead == nil {
-				keptHead = current
-			} else {
-				keptTail.SetNext(current)
-			}
-			keptTail = current
-		} else {
-			current.SetNext(nil)
+		current.SetNext(nil)
+		switch {
+		case p.expired(current):
+			p.destroy(current, EvictExpired)
 			evictedCount++
+		case current.GetUsageCount() >= p.cfg.Cleanup.MinUsageCount && !p.idleTooLong(current, now):
+			passed = append(passed, current)
+		default:
+			failed = append(failed, current)
 		}
 		current = next
 	}
 
-	if keptHead == nil {
-		return nil, nil, evictedCount
+	byUsage := func(a, b P) int { return cmp.Compare(b.GetUsageCount(), a.GetUsageCount()) }
+	if maxKeep != noLimit && len(passed) > max(maxKeep, minKeep) {
+		slices.SortStableFunc(passed, byUsage)
+		n := max(maxKeep, minKeep)
+		failed = append(failed, passed[n:]...)
+		passed = passed[:n]
+	} else if need := minKeep - len(passed); need > 0 && len(failed) > 0 {
+		slices.SortStableFunc(failed, byUsage)
+		n := min(need, len(failed))
+		passed = append(passed, failed[:n]...)
+		failed = failed[n:]
 	}
 
-	keptTail.SetNext(nil)
+	for _, obj := range passed {
+		obj.ResetUsage()
+		if keptHead == nil {
+			keptHead = obj
+		} else {
+			keptTail.SetNext(obj)
+		}
+		keptTail = obj
+	}
+	for _, obj := range failed {
+		p.destroy(obj, EvictCleanup)
+		evictedCount++
+	}
 	return keptHead, keptTail, evictedCount
 }
 
 func (p *ShardedPool[T, P]) reinsertKeptObjects(shard *Shard[T, P], keptHead, keptTail P) {
-	var shardID int
-	for i, s := range p.Shards {
-		if s == shard {
-			shardID = i
-			break
-		}
-	}
-
 	current := keptHead
 	for current != nil {
-		current.SetShardIndex(shardID)
+		current.SetShardIndex(shard.index)
 		current = current.GetNext()
 	}
 
@@ -5,13 +5,17 @@ package pool
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"time"
 )
 
 // Common errors returned by the pool.
 var (
-	ErrNoAllocator = errors.New("no allocator configured")
-	ErrNoCleaner   = errors.New("no cleaner configured")
+	ErrNoAllocator        = errors.New("no allocator configured")
+	ErrNoCleaner          = errors.New("no cleaner configured")
+	ErrPoolExhausted      = errors.New("pool exhausted: MaxPoolSize reached and no object available")
+	ErrPoolClosed         = errors.New("pool closed")
+	ErrObjectsOutstanding = errors.New("objects still checked out")
 )
 
 // GcLevel selects how aggressively the pool reclaims memory. Go's GC may still run.
@@ -29,6 +33,21 @@ type CleanupPolicy struct {
 	Enabled       bool
 	Interval      time.Duration
 	MinUsageCount int64
+
+	// MinIdlePerShard is how many idle objects cleanup always leaves in each shard,
+	// keeping the most-used ones. 0 means cleanup may empty a shard.
+	MinIdlePerShard int
+	// MinIdle is a pool-wide floor, spread evenly over the shards (rounded up). The
+	// larger of the two floors applies.
+	MinIdle int
+	// TargetSize caps the idle objects left after cleanup, split fairly across the
+	// shards; the most-used objects are kept. 0 means no cap. The floors above win
+	// when they are larger than a shard's share.
+	TargetSize int
+	// MaxIdleTime evicts objects that have sat idle longer than this since their last
+	// Put, in addition to the MinUsageCount rule. With MaxIdleTime set, MinUsageCount
+	// may be 0 to evict on idle time alone.
+	MaxIdleTime time.Duration
 }
 
 // DefaultCleanupPolicy returns a CleanupPolicy for the given level; unknown levels use moderate.
@@ -70,16 +89,143 @@ type Config[T any, P Poolable[T]] struct {
 	// Passing a positive value overrides the default (e.g. for testing or to tune contention).
 	NumShards int
 
+	// InitialSize is how many objects NewPoolWithConfig allocates up front (see Prewarm).
+	InitialSize int
+
 	Cleanup   CleanupPolicy
 	Growth    GrowthPolicy
+	Steal     StealPolicy
+	Reshard   ReshardPolicy
 	Allocator Allocator[T]
 	Cleaner   Cleaner[T]
+
+	// MaxLifetime retires objects this long after allocation, however hot they are:
+	// Get and Put destroy expired objects instead of handing them out or pooling them,
+	// and cleanup sweeps them. 0 means objects live until evicted.
+	MaxLifetime time.Duration
+
+	// Validator is optional; Get runs it on reused objects and destroys those it rejects,
+	// moving on to the next candidate or allocating.
+	Validator Validator[T]
+	// ValidateAfter limits validation to objects idle longer than this since their last
+	// Put. 0 validates every reused object.
+	ValidateAfter time.Duration
+
+	// RecoverDropped attaches a runtime cleanup to every object Get hands out, so an
+	// object the caller drops without Put or Discard releases its share of MaxPoolSize
+	// once it is garbage-collected. The Destroyer cannot run for such objects.
+	RecoverDropped bool
+
+	// Destroyer is optional; it runs once for every object the pool drops, after OnEvict.
+	Destroyer Destroyer[T]
+
+	// Hooks are optional lifecycle callbacks; unset hooks cost a nil check.
+	Hooks Hooks[T]
+
+	// TrackStats keeps the per-shard activity counters reported by Stats (hits, misses,
+	// Puts, evictions, CAS retries, steals). Each is an atomic add on the Get/Put path,
+	// so they are off by default; object counts (Live, Idle, InUse) are always kept.
+	TrackStats bool
+
+	// Debug enables checks meant for tests and staging; see DebugOptions.
+	Debug DebugOptions
+	// Poisoner replaces reflection-based poisoning when Debug.Poison is set.
+	Poisoner Poisoner[T]
+}
+
+// DebugOptions turns on debug-mode checks. Each costs extra work on Get/Put, so leave
+// them off in latency-sensitive production pools.
+type DebugOptions struct {
+	// TrackLeaks records the call stack of every Get so LeakReport can list objects
+	// that were never returned with Put.
+	TrackLeaks bool
+	// LeakThreshold is how long an object must be out before LeakReport lists it.
+	LeakThreshold time.Duration
+
+	// CheckPuts stamps objects with their pool and tracks whether they are idle, so Put
+	// detects double returns and objects from another pool. Offending objects are not
+	// pooled; the violation goes to OnViolation, or panics if it is nil.
+	CheckPuts   bool
+	OnViolation func(v *Violation)
+
+	// Poison overwrites every object Put returns (after the Cleaner) with a poison
+	// pattern, and Get checks the pattern is intact before restoring the clean state.
+	// Writes made while the object was idle are reported as ViolationUseAfterPut. T
+	// must be a struct that embeds Fields.
+	Poison bool
+
+	// CheckCleaner compares every object, right after the Cleaner runs in Put, with a
+	// reference object from the Allocator (made once, at construction) and reports the
+	// fields that differ as ViolationDirtyObject. Fields are compared deeply; empty and
+	// nil slices or maps count as equal. T must be a struct that embeds Fields.
+	CheckCleaner bool
+}
+
+// EvictReason says why an object left the pool for good.
+type EvictReason string
+
+const (
+	// EvictCleanup means the background cleanup found the object underused.
+	EvictCleanup EvictReason = "cleanup"
+	// EvictClose means the pool was closed while the object was idle.
+	EvictClose EvictReason = "close"
+	// EvictExpired means the object outlived Config.MaxLifetime.
+	EvictExpired EvictReason = "expired"
+	// EvictInvalid means Config.Validator rejected the object.
+	EvictInvalid EvictReason = "invalid"
+	// EvictDiscard means the caller gave the object up with Discard.
+	EvictDiscard EvictReason = "discard"
+	// EvictSurplus means Put found the object's shard already holding
+	// GrowthPolicy.MaxIdlePerShard idle objects or the pool holding MaxIdle.
+	EvictSurplus EvictReason = "surplus"
+)
+
+// Hooks are lifecycle callbacks. Each receives the object and the index of the shard
+// it belongs to. Hooks run synchronously on the Get/Put/cleanup path, so keep them cheap.
+type Hooks[T any] struct {
+	// OnAllocate runs after the Allocator creates a new object.
+	OnAllocate func(obj *T, shard int)
+	// OnGet runs for every object handed out, new or reused.
+	OnGet func(obj *T, shard int)
+	// OnPut runs when Put accepts an object back, before the Cleaner.
+	OnPut func(obj *T, shard int)
+	// OnEvict runs when an object is dropped from the pool (idle, or passed to Discard).
+	OnEvict func(obj *T, shard int, reason EvictReason)
 }
 
 // GrowthPolicy limits pool size when Enable is true.
 type GrowthPolicy struct {
 	MaxPoolSize int64
 	Enable      bool
+
+	// MaxIdle caps the idle objects across all shards, whether or not Enable is set:
+	// Put destroys an object instead of pooling it once the cap is reached (after
+	// handing it to a blocked GetContext caller, if any). Put sums the idle counts of
+	// all shards to check it. 0 means no cap.
+	MaxIdle int64
+
+	// MaxIdlePerShard is the same cap applied to each shard on its own. Both caps may
+	// be set; Put destroys the object if either is reached. 0 means no cap.
+	MaxIdlePerShard int64
+}
+
+// StealPolicy lets Get take idle objects from sibling shards before allocating.
+type StealPolicy struct {
+	Enable bool
+	// Shards is how many shards after the caller's own are scanned; 0 scans all of them.
+	Shards int
+	// Batch is how many objects are taken from the first non-empty sibling; the extras
+	// are moved to the caller's shard. 0 means 1.
+	Batch int
+}
+
+// ReshardPolicy makes the pool follow runtime.GOMAXPROCS changes, growing or shrinking
+// its shard set online. It requires NumShards to be 0 (one shard per P).
+type ReshardPolicy struct {
+	Enable bool
+	// Interval is how often GOMAXPROCS is checked. A Get running on a P beyond the
+	// current shard count also triggers an immediate check.
+	Interval time.Duration
 }
 
 // DefaultConfig returns a config with moderate cleanup and the given allocator/cleaner.
@@ -102,6 +248,50 @@ func validateConfig[T any, P Poolable[T]](cfg Config[T, P]) error {
 	if cfg.NumShards < 0 {
 		return errors.New("NumShards must be 0 (default) or positive")
 	}
+	if cfg.ValidateAfter < 0 {
+		return errors.New("ValidateAfter must be 0 (default) or positive")
+	}
+	if cfg.ValidateAfter > 0 && cfg.Validator == nil {
+		return errors.New("ValidateAfter requires a Validator")
+	}
+	if cfg.Debug.LeakThreshold < 0 {
+		return errors.New("debug LeakThreshold must be 0 (default) or positive")
+	}
+	if (cfg.Debug.Poison || cfg.Debug.CheckCleaner) && reflect.TypeFor[T]().Kind() != reflect.Struct {
+		return errors.New("debug Poison and CheckCleaner require a struct type")
+	}
+	if (cfg.Debug.Poison || cfg.Debug.CheckCleaner) && reflect.TypeFor[T]().Kind() == reflect.Struct && !embedsFields[T]() {
+		return errors.New("debug Poison and CheckCleaner require T to embed pool.Fields")
+	}
+	if cfg.Poisoner != nil && !cfg.Debug.Poison {
+		return errors.New("Poisoner requires Debug.Poison")
+	}
+	if cfg.MaxLifetime < 0 {
+		return errors.New("MaxLifetime must be 0 (default) or positive")
+	}
+	if cfg.InitialSize < 0 {
+		return errors.New("InitialSize must be 0 (default) or positive")
+	}
+	if cfg.Growth.MaxIdle < 0 {
+		return errors.New("growth MaxIdle must be 0 (default) or positive")
+	}
+	if cfg.Growth.MaxIdlePerShard < 0 {
+		return errors.New("growth MaxIdlePerShard must be 0 (default) or positive")
+	}
+	if cfg.Growth.Enable && int64(cfg.InitialSize) > cfg.Growth.MaxPoolSize {
+		return errors.New("InitialSize must not exceed MaxPoolSize")
+	}
+	if cfg.Steal.Shards < 0 || cfg.Steal.Batch < 0 {
+		return errors.New("steal Shards and Batch must be 0 (default) or positive")
+	}
+	if cfg.Reshard.Enable {
+		if cfg.NumShards != 0 {
+			return errors.New("resharding requires NumShards to be 0 (follow GOMAXPROCS)")
+		}
+		if cfg.Reshard.Interval <= 0 {
+			return errors.New("reshard interval must be greater than 0")
+		}
+	}
 	return nil
 }
 
@@ -109,8 +299,14 @@ func validateCleanupConfig[T any, P Poolable[T]](cfg Config[T, P]) error {
 	if cfg.Cleanup.Interval <= 0 {
 		return errors.New("cleanup interval must be greater than 0")
 	}
-	if cfg.Cleanup.MinUsageCount <= 0 {
+	if cfg.Cleanup.MaxIdleTime < 0 {
+		return errors.New("cleanup MaxIdleTime must be 0 (default) or positive")
+	}
+	if cfg.Cleanup.MinUsageCount < 0 || (cfg.Cleanup.MinUsageCount == 0 && cfg.Cleanup.MaxIdleTime == 0) {
 		return errors.New("minimum usage count must be greater than 0")
 	}
+	if cfg.Cleanup.MinIdlePerShard < 0 || cfg.Cleanup.MinIdle < 0 || cfg.Cleanup.TargetSize < 0 {
+		return errors.New("cleanup MinIdlePerShard, MinIdle and TargetSize must be 0 (default) or positive")
+	}
 	return nil
 }
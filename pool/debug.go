@@ -0,0 +1,198 @@
+// Debug-mode checks, enabled through Config.Debug. They cost a nil check when off and
+// are meant for tests and staging, not hot production paths.
+package pool
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// leakStackDepth is how many frames of the Get call stack are kept per object.
+const leakStackDepth = 32
+
+// Leak describes an object handed out by Get and not yet returned with Put.
+type Leak struct {
+	CheckedOut time.Time
+	Age        time.Duration
+	// Stack is the formatted call stack of the Get that handed the object out.
+	Stack string
+}
+
+// checkoutRecord is what the leak tracker keeps per outstanding object.
+type checkoutRecord struct {
+	at  time.Time
+	pcs []uintptr
+}
+
+// leakTracker records the Get stack of every outstanding object. Records are keyed by
+// a per-checkout ID kept in the object's Meta rather than by the object, so the tracker
+// does not keep dropped objects reachable and a recycled address cannot be mistaken for
+// the collected object that had it.
+type leakTracker struct {
+	mu     sync.Mutex
+	out    map[uint64]checkoutRecord
+	lastID uint64
+}
+
+func newLeakTracker() *leakTracker {
+	return &leakTracker{out: make(map[uint64]checkoutRecord)}
+}
+
+// record stores the caller's stack under a new checkout ID, saved in meta; skip is
+// passed to runtime.Callers.
+func (l *leakTracker) record(meta *metaState, skip int) {
+	pcs := make([]uintptr, leakStackDepth)
+	pcs = pcs[:runtime.Callers(skip+1, pcs)]
+
+	l.mu.Lock()
+	l.lastID++
+	meta.leakID = l.lastID
+	l.out[meta.leakID] = checkoutRecord{at: time.Now(), pcs: pcs}
+	l.mu.Unlock()
+}
+
+// forget drops the record of the checkout meta belongs to. meta is nil for objects
+// the pool did not allocate.
+func (l *leakTracker) forget(meta *metaState) {
+	if meta != nil {
+		l.drop(meta.leakID)
+	}
+}
+
+// drop is forget for an object that no longer exists, such as one collected after
+// being dropped.
+func (l *leakTracker) drop(id uint64) {
+	l.mu.Lock()
+	delete(l.out, id)
+	l.mu.Unlock()
+}
+
+// report lists objects outstanding for longer than threshold, oldest first.
+func (l *leakTracker) report(threshold time.Duration) []Leak {
+	now := time.Now()
+	l.mu.Lock()
+	var leaks []Leak
+	var stacks [][]uintptr
+	for _, rec := range l.out {
+		if age := now.Sub(rec.at); age >= threshold {
+			leaks = append(leaks, Leak{CheckedOut: rec.at, Age: age})
+			stacks = append(stacks, rec.pcs)
+		}
+	}
+	l.mu.Unlock()
+
+	for i := range leaks {
+		leaks[i].Stack = formatStack(stacks[i])
+	}
+	slices.SortFunc(leaks, func(a, b Leak) int { return a.CheckedOut.Compare(b.CheckedOut) })
+	return leaks
+}
+
+// poolMethodPrefix marks the pool's own frames, which formatStack leaves out so the
+// stack starts at the caller of Get.
+const poolMethodPrefix = "github.com/AlexsanderHamir/GenPool/pool.(*ShardedPool["
+
+// formatStack renders pcs one "function\n\tfile:line" entry per frame, like a panic trace.
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, poolMethodPrefix) {
+			b.WriteString(frame.Function)
+			b.WriteString("\n\t")
+			b.WriteString(frame.File)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(frame.Line))
+			b.WriteByte('\n')
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// LeakReport lists objects handed out by Get and not returned with Put for at least
+// Config.Debug.LeakThreshold, oldest first, each with the stack of its Get. It returns
+// nil unless Config.Debug.TrackLeaks is set.
+func (p *ShardedPool[T, P]) LeakReport() []Leak {
+	if p.leaks == nil {
+		return nil
+	}
+	return p.leaks.report(p.cfg.Debug.LeakThreshold)
+}
+
+// ViolationKind names a misuse detected by a debug check.
+type ViolationKind string
+
+const (
+	// ViolationDoublePut means an object was returned while already idle in the pool.
+	ViolationDoublePut ViolationKind = "double put"
+	// ViolationForeignObject means an object allocated by another pool was returned.
+	ViolationForeignObject ViolationKind = "foreign object"
+	// ViolationUseAfterPut means an idle object was written to after Put poisoned it.
+	ViolationUseAfterPut ViolationKind = "use after put"
+	// ViolationDirtyObject means the Cleaner left fields that differ from a freshly
+	// allocated object.
+	ViolationDirtyObject ViolationKind = "dirty object"
+)
+
+// Violation reports a misuse caught by a debug check. It is also the panic value
+// when no OnViolation hook is set.
+type Violation struct {
+	Kind ViolationKind
+	// Fields lists the paths of the fields involved, e.g. "Buf" or "Header.Len";
+	// empty for double and foreign Puts.
+	Fields []string
+	// Stack is the formatted call stack of the Get or Put that detected the violation.
+	Stack string
+}
+
+func (v *Violation) Error() string {
+	if len(v.Fields) > 0 {
+		return fmt.Sprintf("genpool: %s (%s)\n%s", v.Kind, strings.Join(v.Fields, ", "), v.Stack)
+	}
+	return fmt.Sprintf("genpool: %s\n%s", v.Kind, v.Stack)
+}
+
+// violate reports a violation to Debug.OnViolation, or panics with it.
+func (p *ShardedPool[T, P]) violate(kind ViolationKind, fields []string) {
+	pcs := make([]uintptr, leakStackDepth)
+	v := &Violation{Kind: kind, Fields: fields, Stack: formatStack(pcs[:runtime.Callers(2, pcs)])}
+	if p.cfg.Debug.OnViolation == nil {
+		panic(v)
+	}
+	p.cfg.Debug.OnViolation(v)
+}
+
+// poolIDs hands out pool identities for Debug.CheckPuts; 0 means "no pool".
+var poolIDs atomic.Uint64
+
+// checkPut reports whether obj may be returned to p, raising a violation if not. On
+// success obj is marked idle.
+func (p *ShardedPool[T, P]) checkPut(obj P) bool {
+	meta := p.meta(obj)
+	switch {
+	case meta == nil || meta.poolID != p.id:
+		p.violate(ViolationForeignObject, nil)
+	case meta.inPool.Swap(true):
+		p.violate(ViolationDoublePut, nil)
+	default:
+		return true
+	}
+	return false
+}
+
+// markIdle flags obj as idle without going through Put (Prewarm, replenish).
+func (p *ShardedPool[T, P]) markIdle(obj P) {
+	if p.cfg.Debug.CheckPuts {
+		p.meta(obj).inPool.Store(true)
+	}
+}
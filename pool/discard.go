@@ -0,0 +1,61 @@
+// Leak-proof accounting. Discard takes an object out of the pool's books explicitly;
+// Config.RecoverDropped attaches a runtime cleanup to every checked-out object so the
+// budget of objects dropped without Put or Discard is released once they are collected.
+package pool
+
+import (
+	"runtime"
+	"weak"
+)
+
+// Discard permanently removes a checked-out object from the pool instead of returning
+// it: it runs OnEvict (EvictDiscard) and the Destroyer and frees the object's share of
+// GrowthPolicy.MaxPoolSize, waking a blocked GetContext caller if there is one. Use it
+// for objects that are broken or handed off elsewhere.
+func (p *ShardedPool[T, P]) Discard(obj P) {
+	if p.cfg.Debug.CheckPuts && !p.checkPut(obj) {
+		return
+	}
+	if p.leaks != nil {
+		p.leaks.forget(p.meta(obj))
+	}
+	if p.cfg.RecoverDropped {
+		p.meta(obj).dropWatch.Stop()
+	}
+
+	p.destroy(obj, EvictDiscard)
+	p.CurrentPoolLength.Add(-1)
+	p.discarded.Add(1)
+	p.replenish(obj.GetShardIndex())
+	p.wakeShutdown()
+}
+
+// dropTicket is the argument of the runtime cleanup attached to a checked-out object.
+// It must not reference the object, or the object would never be collected.
+type dropTicket[T any, P Poolable[T]] struct {
+	pool   weak.Pointer[ShardedPool[T, P]]
+	leakID uint64 // checkout to forget in the leak tracker
+	shard  int
+}
+
+// watchDrop attaches the drop cleanup to obj as it is handed out.
+func (p *ShardedPool[T, P]) watchDrop(obj P) {
+	ticket := dropTicket[T, P]{pool: weak.Make(p), leakID: p.meta(obj).leakID, shard: obj.GetShardIndex()}
+	p.meta(obj).dropWatch = runtime.AddCleanup((*T)(obj), releaseDropped[T, P], ticket)
+}
+
+// releaseDropped runs after a checked-out object was garbage-collected without Put or
+// Discard, and releases its budget. It does nothing if the pool itself is gone.
+func releaseDropped[T any, P Poolable[T]](ticket dropTicket[T, P]) {
+	p := ticket.pool.Value()
+	if p == nil {
+		return
+	}
+	if p.leaks != nil {
+		p.leaks.drop(ticket.leakID)
+	}
+	p.CurrentPoolLength.Add(-1)
+	p.dropped.Add(1)
+	p.replenish(ticket.shard)
+	p.wakeShutdown()
+}
@@ -0,0 +1,220 @@
+// Use-after-Put poisoning (Debug.Poison), the cleaner completeness check
+// (Debug.CheckCleaner), and the reflection helpers they share. Only the caller's own
+// fields are touched; the embedded Fields the pool relies on are always skipped.
+package pool
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"unsafe"
+)
+
+// poisonBits is the byte pattern written into numeric fields of idle objects.
+const poisonBits = 0xA5A5A5A5A5A5A5A5
+
+// poisonString replaces string fields of idle objects.
+const poisonString = "\xa5genpool: used after Put\xa5"
+
+// poisonedObject is what Put saved about an object it poisoned: the field values the
+// Cleaner left (restored on Get) and the poisoned values (checked on Get).
+type poisonedObject struct {
+	clean, poisoned []reflect.Value
+}
+
+// poisonState tracks the idle objects that are currently poisoned, keyed by address.
+type poisonState struct {
+	mu   sync.Mutex
+	idle map[uintptr]poisonedObject
+}
+
+// embedsFields reports whether T has a top-level Fields[T]. That type is how userFields
+// tells the pool's own state apart; a hand-implemented Poolable keeps its list, usage
+// and shard fields as plain values that would be poisoned and compared like the caller's.
+func embedsFields[T any]() bool {
+	t, want := reflect.TypeFor[T](), reflect.TypeFor[Fields[T]]()
+	for i := range t.NumField() {
+		if t.Field(i).Type == want {
+			return true
+		}
+	}
+	return false
+}
+
+// userFields returns the top-level fields of the struct obj points to, skipping the
+// embedded Fields. Unexported fields are made settable.
+func userFields[T any](obj *T) []reflect.Value {
+	v := reflect.ValueOf(obj).Elem()
+	skip := reflect.TypeFor[Fields[T]]()
+	fields := make([]reflect.Value, 0, v.NumField())
+	for i := range v.NumField() {
+		if f := v.Field(i); f.Type() != skip {
+			fields = append(fields, settable(f))
+		}
+	}
+	return fields
+}
+
+// userFieldNames returns the names of the fields userFields returns, in the same order.
+func userFieldNames[T any]() []string {
+	t := reflect.TypeFor[T]()
+	skip := reflect.TypeFor[Fields[T]]()
+	names := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		if f := t.Field(i); f.Type != skip {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// settable returns an addressable field as a value that can be read and written even
+// if it is unexported.
+func settable(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// copyValues returns detached copies of vs.
+func copyValues(vs []reflect.Value) []reflect.Value {
+	out := make([]reflect.Value, len(vs))
+	for i, v := range vs {
+		out[i] = reflect.New(v.Type()).Elem()
+		out[i].Set(v)
+	}
+	return out
+}
+
+// diffPaths appends to out the path of every leaf where a and b differ, descending into
+// structs and arrays; a and b must have the same type and be settable.
+func diffPaths(path string, a, b reflect.Value, out []string) []string {
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := range a.NumField() {
+			name := a.Type().Field(i).Name
+			out = diffPaths(path+"."+name, settable(a.Field(i)), settable(b.Field(i)), out)
+		}
+		return out
+	case reflect.Array:
+		for i := range a.Len() {
+			out = diffPaths(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i), out)
+		}
+		return out
+	}
+	if !equalLeaf(a, b) {
+		out = append(out, path)
+	}
+	return out
+}
+
+// equalLeaf compares two non-struct, non-array values. Empty slices and maps equal
+// nil ones, since a Cleaner that keeps capacity is doing its job, and funcs only
+// compare by nil-ness because reflect cannot compare them otherwise.
+func equalLeaf(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	case reflect.Func:
+		return a.IsNil() == b.IsNil()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// diffFields compares two lists of userFields and returns the paths that differ.
+func diffFields(names []string, a, b []reflect.Value) []string {
+	var out []string
+	for i := range a {
+		out = diffPaths(names[i], a[i], b[i], out)
+	}
+	return out
+}
+
+// poisonValue overwrites v with a recognizable pattern: numbers get poisonBits, strings
+// poisonString, booleans are flipped, and references are set to nil so a stale user
+// dereferencing them fails fast.
+func poisonValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(!v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(uint64(poisonBits) >> (64 - v.Type().Bits())))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(uint64(poisonBits) >> (64 - v.Type().Bits()))
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(uint32(poisonBits >> 32))))
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(poisonBits))
+	case reflect.Complex64, reflect.Complex128:
+		f := math.Float64frombits(poisonBits)
+		v.SetComplex(complex(f, f))
+	case reflect.String:
+		v.SetString(poisonString)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			poisonValue(settable(v.Field(i)))
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			poisonValue(v.Index(i))
+		}
+	default: // pointers, slices, maps, channels, funcs, interfaces
+		v.SetZero()
+	}
+}
+
+// poison saves obj's clean state and overwrites it, using Config.Poisoner if set.
+func (p *ShardedPool[T, P]) poison(obj P) {
+	fields := userFields[T](obj)
+	saved := poisonedObject{clean: copyValues(fields)}
+	if p.cfg.Poisoner != nil {
+		p.cfg.Poisoner(obj)
+	} else {
+		for _, f := range fields {
+			poisonValue(f)
+		}
+	}
+	saved.poisoned = copyValues(fields)
+
+	p.poisoned.mu.Lock()
+	p.poisoned.idle[uintptr(unsafe.Pointer(obj))] = saved
+	p.poisoned.mu.Unlock()
+}
+
+// unpoison verifies that obj still holds the poison written by Put, reporting any field
+// written since as a ViolationUseAfterPut, and restores the clean state. Objects that
+// were never poisoned (fresh or prewarmed) are left alone.
+func (p *ShardedPool[T, P]) unpoison(obj P) {
+	if p.poisoned != nil {
+		p.restore(obj)
+	}
+}
+
+func (p *ShardedPool[T, P]) restore(obj P) {
+	key := uintptr(unsafe.Pointer(obj))
+	p.poisoned.mu.Lock()
+	saved, ok := p.poisoned.idle[key]
+	delete(p.poisoned.idle, key)
+	p.poisoned.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	fields := userFields[T](obj)
+	if changed := diffFields(userFieldNames[T](), fields, saved.poisoned); len(changed) > 0 {
+		p.violate(ViolationUseAfterPut, changed)
+	}
+	for i, f := range fields {
+		f.Set(saved.clean[i])
+	}
+}
+
+// checkCleaned compares obj, just cleaned by Put, with the reference object allocated
+// at construction and reports the fields the Cleaner left different.
+func (p *ShardedPool[T, P]) checkCleaned(obj P) {
+	dirty := diffFields(userFieldNames[T](), userFields[T](obj), userFields[T](p.reference))
+	if len(dirty) > 0 {
+		p.violate(ViolationDirtyObject, dirty)
+	}
+}
@@ -2,32 +2,75 @@
 // by GOMAXPROCS for better concurrency when there is significant work between Get and Put.
 //
 // This file defines the pool types (Shard, ShardedPool), construction (NewPool,
-// NewPoolWithConfig), Get/Put, clear/Close, and runtime proc pinning linknames.
+// NewPoolWithConfig), Get/Put, clear/Close/Shutdown, and runtime proc pinning linknames.
+// Blocking acquisition (GetContext) lives in wait.go, counters and Stats in stats.go,
+// work stealing in steal.go, GOMAXPROCS-driven resharding in reshard.go, debug-mode
+// checks in debug.go and poison.go, Discard and dropped-object recovery in discard.go.
 package pool
 
 import (
+	"context"
+	"fmt"
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
 // Shard is a single pool shard; padding avoids false sharing across cache lines.
+//
+// Head is a Treiber stack made ABA-safe by allowing only one remover at a time:
+// every pop and take-all holds popMu, while pushes stay lock-free. Between a
+// popper's Load of head X and its CAS, the only other operations are pushes, which
+// add nodes above X but can neither unlink X nor rewrite X's next pointer (next is
+// only written when an object is pushed, and X is already in the stack). So if the
+// CAS still sees X, X.next is the correct new head.
 type Shard[T any, P Poolable[T]] struct {
-	Head   atomic.Pointer[T]
-	Single atomic.Pointer[T]
+	Head     atomic.Pointer[T]
+	Single   atomic.Pointer[T]
+	popMu    sync.Mutex
+	index    int
+	idle     atomic.Int64   // objects on the Head list; Single is counted by looking at it
+	counters *shardCounters // nil unless Config.TrackStats
+	retired  atomic.Bool    // set while resharding has removed this shard from the live set; last, so no gap precedes the pad
 
-	_ [128 - unsafe.Sizeof(atomic.Pointer[T]{})*2]byte
+	_ [128 - unsafe.Sizeof(atomic.Pointer[T]{})*2 - unsafe.Sizeof(sync.Mutex{}) - unsafe.Sizeof(int(0)) -
+		unsafe.Sizeof(atomic.Int64{}) - unsafe.Sizeof(uintptr(0)) - unsafe.Sizeof(atomic.Bool{})]byte
 }
 
 // ShardedPool is the main pool implementation.
 type ShardedPool[T any, P Poolable[T]] struct {
+	// Shards is the shard set created by NewPoolWithConfig. With Config.Reshard enabled
+	// the live set can change afterwards; use CurrentShards to read it.
 	Shards []*Shard[T, P]
 
+	shards      atomic.Pointer[[]*Shard[T, P]] // live shard set used by Get/Put
+	reshardMu   sync.RWMutex                   // serializes resharding and guards allShards
+	allShards   []*Shard[T, P]                 // every shard ever created; live ones first
+	everyShards atomic.Pointer[[]*Shard[T, P]] // allShards, readable without reshardMu
+	reshardKick chan struct{}
+
 	stopClean chan struct{}
+	stopOnce  sync.Once
 	cleanWg   sync.WaitGroup
 	cfg       Config[T, P]
+	waiters   waitQueue[T]
+	drain     waitQueue[T] // Shutdown calls waiting for the in-use count to reach zero
+	closed    atomic.Bool
+	closeOnce sync.Once
+
+	cleanupStats cleanupCounters
+	leaks        *leakTracker // nil unless Config.Debug.TrackLeaks
+	id           uint64       // stamped on objects by Debug.CheckPuts
+	poisoned     *poisonState // nil unless Config.Debug.Poison
+	reference    P            // freshly allocated object for Debug.CheckCleaner; never pooled
+	keepMeta     bool         // some feature needs the per-object state behind Meta
+	discarded    atomic.Int64 // objects given up with Discard
+	dropped      atomic.Int64 // objects collected while checked out (RecoverDropped)
 
+	// CurrentPoolLength counts live objects, idle or in use, and is the budget
+	// GrowthPolicy.MaxPoolSize caps. Prefer TotalCount, IdleCount and InUseCount.
 	CurrentPoolLength atomic.Int64
 }
 
@@ -41,6 +84,11 @@ func NewPoolWithConfig[T any, P Poolable[T]](cfg Config[T, P]) (*ShardedPool[T,
 	if err := validateConfig(cfg); err != nil {
 		return nil, err
 	}
+	if cfg.Cleanup.Enabled {
+		if err := validateCleanupConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
 
 	numShards := cfg.NumShards
 	if numShards <= 0 {
@@ -52,129 +100,625 @@ func NewPoolWithConfig[T any, P Poolable[T]](cfg Config[T, P]) (*ShardedPool[T,
 		stopClean: make(chan struct{}),
 		Shards:    make([]*Shard[T, P], numShards),
 	}
+	if cfg.Debug.TrackLeaks {
+		pool.leaks = newLeakTracker()
+	}
+	if cfg.Debug.CheckPuts {
+		pool.id = poolIDs.Add(1)
+	}
+	if cfg.Debug.Poison {
+		pool.poisoned = &poisonState{idle: make(map[uintptr]poisonedObject)}
+	}
+	if cfg.Debug.CheckCleaner {
+		pool.reference = P(cfg.Allocator())
+	}
+	pool.keepMeta = cfg.Cleanup.MaxIdleTime > 0 || cfg.ValidateAfter > 0 || cfg.MaxLifetime > 0 ||
+		cfg.Debug.CheckPuts || cfg.Debug.TrackLeaks || cfg.RecoverDropped
 
 	initShards(pool)
+	pool.Prewarm(cfg.InitialSize)
 
 	if cfg.Cleanup.Enabled {
-		if err := validateCleanupConfig(cfg); err != nil {
-			return nil, err
-		}
 		pool.startCleaner()
 	}
 
+	if cfg.Reshard.Enable {
+		pool.startResharder()
+	}
+
 	return pool, nil
 }
 
 func initShards[T any, P Poolable[T]](p *ShardedPool[T, P]) {
 	for i := range p.Shards {
-		shard := &Shard[T, P]{}
-		shard.Head.Store(nil)
-		shard.Single.Store(nil)
+		p.Shards[i] = newShard[T, P](i, p.cfg.TrackStats)
+	}
+	p.allShards = append([]*Shard[T, P](nil), p.Shards...)
+	all := p.allShards
+	p.everyShards.Store(&all)
+	live := p.Shards
+	p.shards.Store(&live)
+}
+
+func newShard[T any, P Poolable[T]](index int, trackStats bool) *Shard[T, P] {
+	shard := &Shard[T, P]{index: index}
+	if trackStats {
+		shard.counters = new(shardCounters)
+	}
+	shard.Head.Store(nil)
+	shard.Single.Store(nil)
+	return shard
+}
+
+// shardList returns the live shard set.
+func (p *ShardedPool[T, P]) shardList() []*Shard[T, P] {
+	return *p.shards.Load()
+}
 
-		p.Shards[i] = shard
+// everyShard returns every shard ever created, retired ones included, for counters
+// that must not lose what retired shards hold.
+func (p *ShardedPool[T, P]) everyShard() []*Shard[T, P] {
+	return *p.everyShards.Load()
+}
+
+// CurrentShards returns the live shard set. It equals Shards unless Config.Reshard
+// has changed the shard count; the returned slice must not be modified.
+func (p *ShardedPool[T, P]) CurrentShards() []*Shard[T, P] {
+	return p.shardList()
+}
+
+// homeShard returns the live shard an object belongs to, re-homing objects whose
+// index no longer exists after a shrink.
+func (p *ShardedPool[T, P]) homeShard(obj P) *Shard[T, P] {
+	shards := p.shardList()
+	if idx := obj.GetShardIndex(); idx < len(shards) {
+		return shards[idx]
 	}
+	return p.rehome(obj, shards)
+}
+
+func (p *ShardedPool[T, P]) rehome(obj P, shards []*Shard[T, P]) *Shard[T, P] {
+	idx := obj.GetShardIndex() % len(shards)
+	obj.SetShardIndex(idx)
+	return shards[idx]
 }
 
 // Get returns an object from the pool or allocates a new one. Returns nil if
-// MaxPoolSize is set, reached, and no reusable object is available.
+// MaxPoolSize is set, reached, and no reusable object is available, or if the pool
+// is closed.
 func (p *ShardedPool[T, P]) Get() P {
+	if p.closed.Load() {
+		return nil
+	}
+
+	shards := p.shardList()
 	procID := runtimeProcPin()
-	shardID := procID % len(p.Shards)
-	shard := p.Shards[shardID]
+	shardID := procID % len(shards)
+	shard := shards[shardID]
 	runtimeProcUnpin()
 
-	if single := shard.Single.Load(); single != nil {
-		if shard.Single.CompareAndSwap(single, nil) {
-			P(single).IncrementUsage()
-			return single
+	if procID >= len(shards) && p.reshardKick != nil {
+		p.kickResharder()
+	}
+
+	obj := p.popIdle(shard)
+	if obj == nil && p.cfg.Steal.Enable {
+		obj = p.stealUsable(shard, shardID)
+	}
+	if obj == nil {
+		obj = p.allocate(shard, shardID)
+		if obj == nil {
+			return nil
+		}
+	}
+	return p.handOut(obj)
+}
+
+// handOut checks obj out to the caller, unless the pool was closed after Get looked.
+// Taking obj already raised the in-use count, so either Shutdown sees it and waits, or
+// this sees closed and destroys obj, releasing its budget instead; nil in that case.
+func (p *ShardedPool[T, P]) handOut(obj P) P {
+	if p.closed.Load() {
+		p.destroy(obj, EvictClose)
+		p.CurrentPoolLength.Add(-1)
+		p.wakeShutdown()
+		return nil
+	}
+	p.checkout(obj)
+	return obj
+}
+
+// pop takes an idle object, trying the Single slot before the Head list. single
+// reports which one it came from; obj is nil if the shard is empty.
+func (s *Shard[T, P]) pop() (obj P, single bool) {
+	if cur := P(s.Single.Load()); cur != nil {
+		if s.Single.CompareAndSwap(cur, nil) {
+			return cur, true
 		}
+		s.counters.addRetries(1)
 	}
+	if s.Head.Load() == nil {
+		return nil, false
+	}
+	return s.popHead(), false
+}
+
+// popHead takes the first object of the Head list, or returns nil if it is empty.
+func (s *Shard[T, P]) popHead() P {
+	s.popMu.Lock()
+	defer s.popMu.Unlock()
 
+	var retries int64
 	for {
-		oldHead := P(shard.Head.Load())
+		oldHead := P(s.Head.Load())
 		if oldHead == nil {
 			break
 		}
 
 		next := oldHead.GetNext()
-		if shard.Head.CompareAndSwap(oldHead, next) {
-			oldHead.IncrementUsage()
+		popHook() // no-op unless built with -tags pophook
+		if s.Head.CompareAndSwap(oldHead, next) {
+			s.counters.addRetries(retries)
+			s.idle.Add(-1)
 			return oldHead
 		}
+		retries++ // only a concurrent push can get here
 	}
+	s.counters.addRetries(retries)
+	return nil
+}
+
+// takeAll detaches the whole Head list and returns its first object. It holds popMu
+// so a concurrent pop cannot CAS in a next pointer taken from the detached list.
+func (s *Shard[T, P]) takeAll() P {
+	if s.Head.Load() == nil {
+		return nil
+	}
+
+	s.popMu.Lock()
+	defer s.popMu.Unlock()
+	return s.Head.Swap(nil)
+}
+
+// push parks an idle object in the Single slot, or on the Head list if Single is taken.
+func (s *Shard[T, P]) push(obj P) {
+	if !s.Single.CompareAndSwap(nil, obj) {
+		s.pushHead(obj)
+	}
+}
+
+// pushHead puts obj on top of the Head list.
+func (s *Shard[T, P]) pushHead(obj P) {
+	var retries int64
+	for {
+		oldHead := P(s.Head.Load())
+		obj.SetNext(oldHead) // before CAS so Get never sees wrong next (#31, #32)
+		if s.Head.CompareAndSwap(oldHead, obj) {
+			s.idle.Add(1)
+			s.counters.addRetries(retries)
+			return
+		}
+		retries++
+	}
+}
+
+// stealUsable steals an object from a sibling shard, destroying expired and invalid
+// ones on the way; nil means nothing reusable was found.
+func (p *ShardedPool[T, P]) stealUsable(shard *Shard[T, P], shardID int) P {
+	for {
+		obj := p.steal(shard, shardID)
+		if obj == nil {
+			return nil
+		}
+		p.unpoison(obj)
+		reason, bad := p.unusable(obj)
+		if !bad {
+			return obj
+		}
+		p.retire(obj, reason)
+	}
+}
+
+// popIdle takes an object from the shard and records the hit, or returns nil.
+func (p *ShardedPool[T, P]) popIdle(shard *Shard[T, P]) P {
+	for {
+		obj, single := shard.pop()
+		if obj == nil {
+			return nil
+		}
+		p.unpoison(obj)
+		if reason, bad := p.unusable(obj); bad {
+			p.retire(obj, reason)
+			continue
+		}
 
-	// Direct allocation path
-	if !p.cfg.Growth.Enable {
-		obj := P(p.cfg.Allocator())
-		obj.SetShardIndex(shardID)
-		obj.IncrementUsage()
-		p.CurrentPoolLength.Add(1)
+		if c := shard.counters; c != nil {
+			if single {
+				c.singleHits.Add(1)
+			} else {
+				c.headHits.Add(1)
+			}
+		}
 		return obj
 	}
+}
 
-	if p.CurrentPoolLength.Load() >= p.cfg.Growth.MaxPoolSize {
+// allocate creates a new object for shardID, or returns nil if the growth cap is reached.
+func (p *ShardedPool[T, P]) allocate(shard *Shard[T, P], shardID int) P {
+	if !p.claim() {
+		if c := shard.counters; c != nil {
+			c.capRejections.Add(1)
+		}
 		return nil
 	}
+	if c := shard.counters; c != nil {
+		c.misses.Add(1)
+	}
+	return p.newObject(shardID)
+}
+
+// claim takes one unit of budget for a new object: a MaxPoolSize slot with a growth
+// policy, a plain CurrentPoolLength increment without one.
+func (p *ShardedPool[T, P]) claim() bool {
+	if p.cfg.Growth.Enable {
+		return p.reserve()
+	}
+	p.CurrentPoolLength.Add(1)
+	return true
+}
 
+// newObject calls the Allocator and prepares the result for shardID.
+func (p *ShardedPool[T, P]) newObject(shardID int) P {
 	obj := P(p.cfg.Allocator())
 	obj.SetShardIndex(shardID)
-	obj.IncrementUsage()
-	p.CurrentPoolLength.Add(1)
+	if p.keepMeta {
+		p.attachMeta(obj)
+	}
+	if p.cfg.Hooks.OnAllocate != nil {
+		p.cfg.Hooks.OnAllocate(obj, shardID)
+	}
 	return obj
 }
 
-func (p *ShardedPool[T, P]) Put(obj P) {
-	p.cfg.Cleaner(obj)
+// attachMeta allocates the state behind obj's Meta and stamps what is known at
+// allocation.
+func (p *ShardedPool[T, P]) attachMeta(obj P) {
+	state := &metaState{poolID: p.id}
+	if p.cfg.MaxLifetime > 0 {
+		state.createdAt = time.Now().UnixNano()
+	}
+	obj.PoolMeta().state = state
+}
+
+// meta returns the state behind obj's Meta; nil if obj was not allocated by a pool
+// that keeps it.
+func (p *ShardedPool[T, P]) meta(obj P) *metaState {
+	return obj.PoolMeta().state
+}
+
+// expired reports whether obj has outlived Config.MaxLifetime.
+func (p *ShardedPool[T, P]) expired(obj P) bool {
+	return p.cfg.MaxLifetime > 0 && p.outlived(obj)
+}
 
-	shardID := obj.GetShardIndex()
-	shard := p.Shards[shardID]
+func (p *ShardedPool[T, P]) outlived(obj P) bool {
+	return time.Now().UnixNano()-p.meta(obj).createdAt > int64(p.cfg.MaxLifetime)
+}
+
+// unusable reports why a reused object must be destroyed instead of handed out: it
+// outlived MaxLifetime or the Validator rejected it. The check is split so the common
+// case, with neither configured, inlines into Get.
+func (p *ShardedPool[T, P]) unusable(obj P) (reason EvictReason, bad bool) {
+	if p.cfg.MaxLifetime == 0 && p.cfg.Validator == nil {
+		return "", false
+	}
+	return p.vet(obj)
+}
+
+func (p *ShardedPool[T, P]) vet(obj P) (reason EvictReason, bad bool) {
+	if p.expired(obj) {
+		return EvictExpired, true
+	}
+	if p.cfg.Validator == nil {
+		return "", false
+	}
+	if after := p.cfg.ValidateAfter; after > 0 && time.Now().UnixNano()-p.meta(obj).lastUsed.Load() <= int64(after) {
+		return "", false
+	}
+	if !p.cfg.Validator(obj) {
+		return EvictInvalid, true
+	}
+	return "", false
+}
+
+// idleFull reports whether shard holds MaxIdlePerShard idle objects or the pool holds
+// GrowthPolicy.MaxIdle. MaxIdle is checked against the sum over all shards: splitting
+// it into per-shard shares would turn objects away from a shard whose share is full
+// (or 0, when MaxIdle is below the shard count) while the pool still had room.
+func (p *ShardedPool[T, P]) idleFull(shard *Shard[T, P]) bool {
+	growth := p.cfg.Growth
+	if growth.MaxIdlePerShard > 0 && shard.idleLen() >= growth.MaxIdlePerShard {
+		return true
+	}
+	return growth.MaxIdle > 0 && p.IdleCount() >= growth.MaxIdle
+}
+
+// retire destroys an object that is out of its shard (taken by Get or returned by Put)
+// and releases its budget to a blocked GetContext caller, if any.
+func (p *ShardedPool[T, P]) retire(obj P, reason EvictReason) {
+	p.destroy(obj, reason)
+	p.CurrentPoolLength.Add(-1)
+	if c := p.homeShard(obj).counters; c != nil {
+		c.evictions.Add(1)
+	}
+	p.replenish(obj.GetShardIndex())
+	p.wakeShutdown()
+}
 
-	if shard.Single.CompareAndSwap(nil, obj) {
+// replenish allocates an object for the oldest GetContext caller after an object left
+// the pool without going back to a shard, since that caller is waiting for a Put that
+// no longer comes.
+func (p *ShardedPool[T, P]) replenish(shardID int) {
+	if p.waiters.count.Load() == 0 || p.closed.Load() || !p.claim() {
 		return
 	}
+	shards := p.shardList()
+	shardID %= len(shards)
+	if c := shards[shardID].counters; c != nil {
+		c.misses.Add(1)
+	}
+	obj := p.newObject(shardID)
+	p.touch(obj)
+	p.markIdle(obj)
+	p.recycle(obj)
+}
+
+// Prewarm allocates up to n objects and parks them idle, spread round-robin across the
+// live shards, skipping shards at MaxIdlePerShard. It stops early at MaxPoolSize, at
+// MaxIdle or once every shard is full, and returns how
+// many objects were created. OnAllocate runs for each of them; they do not count as
+// Get misses.
+func (p *ShardedPool[T, P]) Prewarm(n int) int {
+	shards := p.shardList()
+	created := 0
+	// full counts the shards in a row found at their idle cap; a whole round of them
+	// means there is nowhere left to park an object.
+	for i, full := 0, 0; created < n && full < len(shards) && !p.closed.Load(); i++ {
+		shard := shards[i%len(shards)]
+		if p.idleFull(shard) {
+			full++
+			continue
+		}
+		full = 0
+		if !p.claim() {
+			break
+		}
+		obj := p.newObject(shard.index)
+		p.touch(obj)
+		p.markIdle(obj)
+		p.recycle(obj)
+		created++
+	}
+	return created
+}
+
+// touch records when obj went idle; only idle-time cleanup and ValidateAfter need it,
+// so it is skipped otherwise to keep time.Now off the Put path.
+func (p *ShardedPool[T, P]) touch(obj P) {
+	if p.cfg.Cleanup.MaxIdleTime > 0 || p.cfg.ValidateAfter > 0 {
+		p.stamp(obj)
+	}
+}
+
+func (p *ShardedPool[T, P]) stamp(obj P) {
+	p.meta(obj).lastUsed.Store(time.Now().UnixNano())
+}
+
+// checkout marks obj as handed out to a caller.
+func (p *ShardedPool[T, P]) checkout(obj P) {
+	p.unpoison(obj)
+	if p.leaks != nil {
+		p.leaks.record(p.meta(obj), 2)
+	}
+	if p.cfg.Debug.CheckPuts {
+		p.meta(obj).inPool.Store(false)
+	}
+	if p.cfg.RecoverDropped {
+		p.watchDrop(obj)
+	}
+	obj.IncrementUsage()
+	if p.cfg.Hooks.OnGet != nil {
+		p.cfg.Hooks.OnGet(obj, obj.GetShardIndex())
+	}
+}
+
+// destroy runs the eviction hook and the Destroyer for an object that is leaving the
+// pool for good. Callers adjust CurrentPoolLength and the shard counters themselves.
+func (p *ShardedPool[T, P]) destroy(obj P, reason EvictReason) {
+	p.unpoison(obj)
+	if p.cfg.Hooks.OnEvict != nil {
+		p.cfg.Hooks.OnEvict(obj, obj.GetShardIndex(), reason)
+	}
+	if p.cfg.Destroyer != nil {
+		p.cfg.Destroyer(obj)
+	}
+}
+
+// TryGet is like Get but reports why no object was returned: ErrPoolClosed after
+// Close, or ErrPoolExhausted when MaxPoolSize is reached and nothing is idle.
+func (p *ShardedPool[T, P]) TryGet() (P, error) {
+	if obj := p.Get(); obj != nil {
+		return obj, nil
+	}
+	if p.closed.Load() {
+		return nil, ErrPoolClosed
+	}
+	return nil, ErrPoolExhausted
+}
 
+// reserve claims one slot of the MaxPoolSize budget; the CAS keeps concurrent
+// allocations from overshooting the cap.
+func (p *ShardedPool[T, P]) reserve() bool {
 	for {
-		oldHead := P(shard.Head.Load())
-		obj.SetNext(oldHead) // before CAS so Get never sees wrong next (#31, #32)
-		if shard.Head.CompareAndSwap(oldHead, obj) {
-			return
+		n := p.CurrentPoolLength.Load()
+		if n >= p.cfg.Growth.MaxPoolSize {
+			return false
+		}
+		if p.CurrentPoolLength.CompareAndSwap(n, n+1) {
+			return true
 		}
 	}
 }
 
-// clear removes all objects from the pool and updates CurrentPoolLength.
+// Put cleans obj and returns it to its shard, or hands it to a caller blocked in GetContext.
+// After Close the object is destroyed instead.
+func (p *ShardedPool[T, P]) Put(obj P) {
+	if p.cfg.Debug.CheckPuts && !p.checkPut(obj) {
+		return
+	}
+	if p.leaks != nil {
+		p.leaks.forget(p.meta(obj))
+	}
+	if p.cfg.RecoverDropped {
+		p.meta(obj).dropWatch.Stop()
+	}
+
+	if p.closed.Load() {
+		p.destroy(obj, EvictClose)
+		p.CurrentPoolLength.Add(-1)
+		p.wakeShutdown()
+		return
+	}
+
+	if p.expired(obj) {
+		p.retire(obj, EvictExpired)
+		return
+	}
+
+	if p.cfg.Hooks.OnPut != nil {
+		p.cfg.Hooks.OnPut(obj, obj.GetShardIndex())
+	}
+	p.cfg.Cleaner(obj)
+	if p.reference != nil {
+		p.checkCleaned(obj)
+	}
+	p.touch(obj)
+	if p.poisoned != nil {
+		p.poison(obj)
+	}
+	if p.cfg.TrackStats {
+		p.homeShard(obj).counters.puts.Add(1)
+	}
+	p.recycle(obj)
+}
+
+// recycle returns an already-cleaned object to a waiter or to its shard.
+func (p *ShardedPool[T, P]) recycle(obj P) {
+	if p.waiters.handoff(obj) {
+		return
+	}
+	shard := p.homeShard(obj)
+	if p.idleFull(shard) {
+		p.retire(obj, EvictSurplus)
+		return
+	}
+	shard.push(obj)
+	p.settle(shard)
+
+	// Close may have drained the pool between loading the shard and the push.
+	if p.closed.Load() {
+		p.clear()
+		p.wakeShutdown()
+	}
+}
+
+// clear removes all idle objects, Single slots and retired shards included, and
+// updates CurrentPoolLength.
 func (p *ShardedPool[T, P]) clear() {
-	for _, shard := range p.Shards {
-		for {
-			current := P(shard.Head.Load())
-			if current == nil {
-				break
-			}
+	p.reshardMu.RLock()
+	defer p.reshardMu.RUnlock()
 
-			if shard.Head.CompareAndSwap(current, nil) {
-				removedCount := int64(0)
-				for current != nil {
-					next := current.GetNext()
-					current.SetNext(nil)
-					p.cfg.Cleaner(current)
-					removedCount++
-					current = next
-				}
-				if removedCount > 0 {
-					p.CurrentPoolLength.Add(-removedCount)
-				}
-				break
-			}
+	for _, shard := range p.allShards {
+		current := p.tryTakeOwnership(shard)
+		removedCount := int64(0)
+		for current != nil {
+			next := current.GetNext()
+			current.SetNext(nil)
+			p.destroy(current, EvictClose)
+			removedCount++
+			current = next
+		}
+		if removedCount > 0 {
+			shard.idle.Add(-removedCount)
+			p.CurrentPoolLength.Add(-removedCount)
 		}
 	}
 }
 
-// Close stops the cleanup goroutine and clears the pool.
+// Close stops the background goroutines, releases callers blocked in GetContext with
+// ErrPoolClosed, and destroys every idle object. Afterwards Get returns nil, TryGet
+// returns ErrPoolClosed and Put destroys the objects it is given. Close is safe to
+// call more than once; later calls wait for the first to finish.
 func (p *ShardedPool[T, P]) Close() {
-	if p.cfg.Cleanup.Enabled {
+	p.closeOnce.Do(func() {
+		p.closed.Store(true)
+		p.waiters.closeAll()
+
+		p.stopBackground()
+		p.clear()
+
+		if p.reference != nil && p.cfg.Destroyer != nil {
+			p.cfg.Destroyer(p.reference)
+		}
+	})
+}
+
+// stopBackground stops the cleanup and resharding goroutines and waits for them.
+func (p *ShardedPool[T, P]) stopBackground() {
+	p.stopOnce.Do(func() {
 		close(p.stopClean)
 		p.cleanWg.Wait()
-		p.clear()
+	})
+}
+
+// Shutdown stops new Gets (as Close does), waits until every object handed out has
+// been returned with Put or ctx is done, then closes the pool, destroying idle objects.
+// If ctx ends first it still closes the pool and returns an error wrapping
+// ErrObjectsOutstanding and ctx.Err() with the number of objects still out; those
+// objects are destroyed when they are eventually Put.
+func (p *ShardedPool[T, P]) Shutdown(ctx context.Context) error {
+	p.closed.Store(true)
+	p.waiters.closeAll()
+	// Cleanup and resharding briefly move objects out of the idle count, which would
+	// read as checked out; nothing would wake the loop once they put them back.
+	p.stopBackground()
+
+	for {
+		// Enqueue before counting: whatever brings the count to zero afterwards
+		// sees the waiter and wakes it.
+		w := p.drain.enqueue()
+		if p.InUseCount() == 0 {
+			p.drain.remove(w)
+			break
+		}
+		select {
+		case <-w.ch:
+		case <-ctx.Done():
+			p.drain.remove(w)
+			p.Close()
+			return fmt.Errorf("%w: %d: %w", ErrObjectsOutstanding, p.InUseCount(), ctx.Err())
+		}
+	}
+
+	p.Close()
+	return nil
+}
+
+// wakeShutdown releases waiting Shutdown calls once nothing is checked out. Every path
+// that can lower the in-use count after the pool is closed calls it.
+func (p *ShardedPool[T, P]) wakeShutdown() {
+	if p.drain.count.Load() > 0 && p.InUseCount() == 0 {
+		p.drain.closeAll()
 	}
 }
 
@@ -2,7 +2,10 @@
 // usage count, and shard index; implement Poolable so they can be used with ShardedPool.
 package pool
 
-import "sync/atomic"
+import (
+	"runtime"
+	"sync/atomic"
+)
 
 // Allocator creates new objects for the pool.
 type Allocator[T any] func() *T
@@ -10,6 +13,18 @@ type Allocator[T any] func() *T
 // Cleaner prepares an object before it is returned to the pool.
 type Cleaner[T any] func(*T)
 
+// Destroyer releases an object's resources when it leaves the pool for good
+// (cleanup eviction, Close). It runs exactly once per object and never on Put.
+type Destroyer[T any] func(*T)
+
+// Validator reports whether an idle object is still fit to hand out (e.g. its
+// connection is alive). Objects it rejects are destroyed.
+type Validator[T any] func(*T) bool
+
+// Poisoner overwrites an idle object with a recognizable pattern for Debug.Poison,
+// replacing the default reflection-based poisoning. It must leave the embedded Fields alone.
+type Poisoner[T any] func(*T)
+
 // Poolable is the interface required to store objects in the pool.
 type Poolable[T any] interface {
 	*T
@@ -20,6 +35,25 @@ type Poolable[T any] interface {
 	ResetUsage()
 	SetShardIndex(index int)
 	GetShardIndex() int
+	PoolMeta() *Meta
+}
+
+// Meta is the pool's bookkeeping for optional features (idle time, MaxLifetime, debug
+// checks, dropped-object recovery). It is opaque and costs one pointer per object; the
+// rest is allocated only when the pool's config needs it. Fields holds one; a type that
+// implements Poolable by hand keeps a Meta field and returns its address from PoolMeta.
+type Meta struct {
+	state *metaState
+}
+
+// metaState is allocated by newObject when one of the features behind Meta is on.
+type metaState struct {
+	lastUsed  atomic.Int64    // Unix nanoseconds of the last Put; only kept with MaxIdleTime or ValidateAfter
+	createdAt int64           // Unix nanoseconds of allocation; only kept with Config.MaxLifetime
+	poolID    uint64          // owning pool; only kept with Debug.CheckPuts
+	inPool    atomic.Bool     // idle in the pool rather than checked out; only kept with Debug.CheckPuts
+	dropWatch runtime.Cleanup // pending drop cleanup while checked out; only kept with Config.RecoverDropped
+	leakID    uint64          // current checkout in the leak tracker; only kept with Debug.TrackLeaks
 }
 
 // Fields provides the intrusive fields and Poolable implementation; embed in your type.
@@ -27,6 +61,7 @@ type Fields[T any] struct {
 	usageCount atomic.Int64
 	next       atomic.Pointer[T]
 	shardIndex int
+	meta       Meta
 }
 
 func (p *Fields[T]) GetNext() *T {
@@ -56,3 +91,7 @@ func (p *Fields[T]) SetShardIndex(index int) {
 func (p *Fields[T]) GetShardIndex() int {
 	return p.shardIndex
 }
+
+func (p *Fields[T]) PoolMeta() *Meta {
+	return &p.meta
+}
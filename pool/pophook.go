@@ -0,0 +1,14 @@
+//go:build pophook
+
+package pool
+
+// popHookFn, when set by a test, runs inside popHead between loading the head's next
+// pointer and the CAS, where an unserialized pop would be exposed to ABA. It only
+// exists in builds with the pophook tag, so regular builds pay nothing for it.
+var popHookFn func()
+
+func popHook() {
+	if popHookFn != nil {
+		popHookFn()
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !pophook
+
+package pool
+
+// popHook is the test seam in popHead; see pophook.go. Without the pophook tag it is
+// empty and inlines away.
+func popHook() {}
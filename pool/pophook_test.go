@@ -0,0 +1,51 @@
+//go:build pophook
+
+package pool
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestPopABA forces the classic ABA interleaving on the Head list: while one pop sits
+// between loading A.next and its CAS, another goroutine pops A and B and pushes A back.
+// Without serialized pops the stale CAS would reinstall B while it is held.
+// Run with: go test -tags pophook -run TestPopABA ./pool/
+func TestPopABA(t *testing.T) {
+	shard := newShard[TestObject, *TestObject](0, false)
+	a, b, c := &TestObject{ID: 1}, &TestObject{ID: 2}, &TestObject{ID: 3}
+	shard.pushHead(c)
+	shard.pushHead(b)
+	shard.pushHead(a) // Head: a -> b -> c
+
+	var held []*TestObject
+	done := make(chan struct{})
+	popHookFn = func() {
+		popHookFn = nil
+		go func() {
+			defer close(done)
+			x, y := shard.popHead(), shard.popHead()
+			shard.pushHead(x)
+			held = append(held, y)
+		}()
+		select {
+		case <-done: // only possible if pops are not serialized
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	defer func() { popHookFn = nil }()
+
+	first := shard.popHead()
+	<-done
+	held = append(held, first)
+
+	for obj := shard.Head.Load(); obj != nil; obj = obj.GetNext() {
+		if slices.Contains(held, obj) {
+			t.Fatalf("object %d is held by a caller and still on the Head list", obj.ID)
+		}
+	}
+	if n := shard.idleLen(); n != 1 {
+		t.Errorf("idleLen() = %d, want 1", n)
+	}
+}
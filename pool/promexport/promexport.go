@@ -0,0 +1,297 @@
+// Package promexport serves ShardedPool statistics in the Prometheus text exposition
+// format (version 0.0.4). It implements the format itself so the pool module stays
+// free of dependencies; mount an Exporter on any http.ServeMux and point a Prometheus
+// scrape job (or another registry's handler chain) at it.
+//
+// Object gauges are always populated; the activity counters (hits, allocations, puts
+// and so on) stay at zero unless the pool was built with Config.TrackStats.
+package promexport
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/AlexsanderHamir/GenPool/pool"
+)
+
+// ContentType is the Content-Type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Common errors returned by Register.
+var (
+	ErrEmptyName     = errors.New("pool name must not be empty")
+	ErrDuplicateName = errors.New("pool name already registered")
+)
+
+// Source is anything that can report pool statistics; every *pool.ShardedPool is a Source.
+type Source interface {
+	Stats() pool.Stats
+}
+
+// Exporter exposes the statistics of one or more named pools. The zero value is not
+// usable; create one with New.
+type Exporter struct {
+	mu    sync.RWMutex
+	pools map[string]Source
+}
+
+// New returns an empty Exporter.
+func New() *Exporter {
+	return &Exporter{pools: make(map[string]Source)}
+}
+
+// Register adds src under name, which becomes the value of the "pool" label.
+func (e *Exporter) Register(name string, src Source) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.pools[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+	e.pools[name] = src
+	return nil
+}
+
+// Unregister removes the pool registered under name and reports whether it existed.
+func (e *Exporter) Unregister(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.pools[name]
+	delete(e.pools, name)
+	return ok
+}
+
+// ServeHTTP writes the current metrics of every registered pool.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = e.WriteTo(w)
+}
+
+// snapshot is one pool's statistics taken for a single scrape.
+type snapshot struct {
+	name  string
+	stats pool.Stats
+}
+
+// WriteTo writes the current metrics of every registered pool to w, grouped by metric
+// family and ordered by pool name.
+func (e *Exporter) WriteTo(w io.Writer) (int64, error) {
+	e.mu.RLock()
+	snaps := make([]snapshot, 0, len(e.pools))
+	for name, src := range e.pools {
+		snaps = append(snaps, snapshot{name: name, stats: src.Stats()})
+	}
+	e.mu.RUnlock()
+
+	slices.SortFunc(snaps, func(a, b snapshot) int { return strings.Compare(a.name, b.name) })
+
+	cw := &countingWriter{w: w}
+	tw := &textWriter{w: bufio.NewWriter(cw)}
+	for _, f := range families {
+		tw.header(f)
+		for _, snap := range snaps {
+			f.write(tw, snap)
+		}
+	}
+	if err := tw.w.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, tw.err
+}
+
+// family is one metric family; write emits its samples for a single pool.
+type family struct {
+	name  string
+	help  string
+	typ   string
+	write func(tw *textWriter, snap snapshot)
+}
+
+// perShard returns a write func that emits one sample per shard using value.
+func perShard(name string, value func(pool.ShardStats) int64) func(*textWriter, snapshot) {
+	return func(tw *textWriter, snap snapshot) {
+		for i, shard := range snap.stats.Shards {
+			tw.sample(name, float64(value(shard)), "pool", snap.name, "shard", strconv.Itoa(i))
+		}
+	}
+}
+
+// perPool returns a write func that emits one sample for the whole pool using value.
+func perPool(name string, value func(pool.Stats) float64) func(*textWriter, snapshot) {
+	return func(tw *textWriter, snap snapshot) {
+		tw.sample(name, value(snap.stats), "pool", snap.name)
+	}
+}
+
+var families = []family{
+	{
+		name: "genpool_hits_total",
+		help: "Get calls served by a pooled object, by slot.",
+		typ:  "counter",
+		write: func(tw *textWriter, snap snapshot) {
+			for i, shard := range snap.stats.Shards {
+				id := strconv.Itoa(i)
+				tw.sample("genpool_hits_total", float64(shard.SingleHits), "pool", snap.name, "shard", id, "slot", "single")
+				tw.sample("genpool_hits_total", float64(shard.HeadHits), "pool", snap.name, "shard", id, "slot", "head")
+			}
+		},
+	},
+	{
+		name:  "genpool_allocations_total",
+		help:  "Get calls that called the allocator.",
+		typ:   "counter",
+		write: perShard("genpool_allocations_total", func(s pool.ShardStats) int64 { return s.Misses }),
+	},
+	{
+		name:  "genpool_cap_rejections_total",
+		help:  "Get calls that found nothing because MaxPoolSize was reached.",
+		typ:   "counter",
+		write: perShard("genpool_cap_rejections_total", func(s pool.ShardStats) int64 { return s.CapRejections }),
+	},
+	{
+		name:  "genpool_puts_total",
+		help:  "Objects returned with Put.",
+		typ:   "counter",
+		write: perShard("genpool_puts_total", func(s pool.ShardStats) int64 { return s.Puts }),
+	},
+	{
+		name:  "genpool_evictions_total",
+		help:  "Objects dropped by cleanup, MaxLifetime, the Validator or MaxIdle.",
+		typ:   "counter",
+		write: perShard("genpool_evictions_total", func(s pool.ShardStats) int64 { return s.Evictions }),
+	},
+	{
+		name:  "genpool_cas_retries_total",
+		help:  "Failed compare-and-swaps on shard lists.",
+		typ:   "counter",
+		write: perShard("genpool_cas_retries_total", func(s pool.ShardStats) int64 { return s.CASRetries }),
+	},
+	{
+		name:  "genpool_steals_total",
+		help:  "Get calls served by stealing from a sibling shard.",
+		typ:   "counter",
+		write: perShard("genpool_steals_total", func(s pool.ShardStats) int64 { return s.Steals }),
+	},
+	{
+		name:  "genpool_stolen_objects_total",
+		help:  "Objects taken from sibling shards, including batch extras.",
+		typ:   "counter",
+		write: perShard("genpool_stolen_objects_total", func(s pool.ShardStats) int64 { return s.Stolen }),
+	},
+	{
+		name:  "genpool_idle_objects",
+		help:  "Objects sitting in the shard, ready for Get.",
+		typ:   "gauge",
+		write: perShard("genpool_idle_objects", func(s pool.ShardStats) int64 { return s.Idle }),
+	},
+	{
+		name:  "genpool_in_use_objects",
+		help:  "Objects handed out and not yet returned.",
+		typ:   "gauge",
+		write: perPool("genpool_in_use_objects", func(s pool.Stats) float64 { return float64(s.InUse) }),
+	},
+	{
+		name:  "genpool_live_objects",
+		help:  "Objects the pool accounts for, idle or in use.",
+		typ:   "gauge",
+		write: perPool("genpool_live_objects", func(s pool.Stats) float64 { return float64(s.Live) }),
+	},
+	{
+		name:  "genpool_discarded_objects_total",
+		help:  "Objects given up with Discard.",
+		typ:   "counter",
+		write: perPool("genpool_discarded_objects_total", func(s pool.Stats) float64 { return float64(s.Discarded) }),
+	},
+	{
+		name:  "genpool_dropped_objects_total",
+		help:  "Objects garbage-collected while checked out and recovered.",
+		typ:   "counter",
+		write: perPool("genpool_dropped_objects_total", func(s pool.Stats) float64 { return float64(s.Dropped) }),
+	},
+	{
+		name:  "genpool_hit_ratio",
+		help:  "Fraction of Get calls served by a pooled object.",
+		typ:   "gauge",
+		write: perPool("genpool_hit_ratio", func(s pool.Stats) float64 { return s.Total.HitRatio() }),
+	},
+	{
+		name: "genpool_cleanup_duration_seconds",
+		help: "Time spent in background cleanup passes.",
+		typ:  "summary",
+		write: func(tw *textWriter, snap snapshot) {
+			tw.sample("genpool_cleanup_duration_seconds_sum", snap.stats.CleanupTime.Seconds(), "pool", snap.name)
+			tw.sample("genpool_cleanup_duration_seconds_count", float64(snap.stats.CleanupRuns), "pool", snap.name)
+		},
+	},
+	{
+		name:  "genpool_cleanup_last_duration_seconds",
+		help:  "Duration of the most recent cleanup pass.",
+		typ:   "gauge",
+		write: perPool("genpool_cleanup_last_duration_seconds", func(s pool.Stats) float64 { return s.LastCleanup.Seconds() }),
+	},
+}
+
+// textWriter emits exposition-format lines and keeps the first write error.
+type textWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (tw *textWriter) header(f family) {
+	tw.printf("# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ)
+}
+
+// sample writes one sample; labels are name/value pairs.
+func (tw *textWriter) sample(name string, value float64, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(labels[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteString("} ")
+	b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	b.WriteByte('\n')
+	tw.printf("%s", b.String())
+}
+
+func (tw *textWriter) printf(format string, args ...any) {
+	if tw.err != nil {
+		return
+	}
+	_, tw.err = fmt.Fprintf(tw.w, format, args...)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+// countingWriter counts bytes written for WriteTo's return value.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,167 @@
+package promexport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/GenPool/pool"
+)
+
+type testObject struct {
+	Value int
+	pool.Fields[testObject]
+}
+
+func newTestPool(t *testing.T) *pool.ShardedPool[testObject, *testObject] {
+	t.Helper()
+	cfg := pool.Config[testObject, *testObject]{
+		NumShards:  1,
+		TrackStats: true,
+		Allocator:  func() *testObject { return &testObject{} },
+		Cleaner:    func(obj *testObject) { obj.Value = 0 },
+	}
+	p, err := pool.NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(p.Close)
+	return p
+}
+
+// staticSource reports fixed statistics so the output can be compared exactly.
+type staticSource pool.Stats
+
+func (s staticSource) Stats() pool.Stats { return pool.Stats(s) }
+
+// TestServeHTTP scrapes a live pool through an httptest server and checks the families are present.
+func TestServeHTTP(t *testing.T) {
+	p := newTestPool(t)
+	obj := p.Get()
+	p.Put(obj)
+	_ = p.Get()
+
+	exp := New()
+	if err := exp.Register("requests", p); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(exp)
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, http.NoBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(body)
+
+	for _, f := range families {
+		if !strings.Contains(text, "# TYPE "+f.name+" "+f.typ+"\n") {
+			t.Errorf("missing TYPE line for %s", f.name)
+		}
+	}
+	for _, want := range []string{
+		`genpool_hit_ratio{pool="requests"} 0.5` + "\n",
+		`genpool_live_objects{pool="requests"} 1` + "\n",
+		`genpool_in_use_objects{pool="requests"} 1` + "\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output missing %q\n%s", want, text)
+		}
+	}
+}
+
+// TestWriteToMultiplePools checks grouping by family, ordering by pool name and label escaping.
+func TestWriteToMultiplePools(t *testing.T) {
+	exp := New()
+	shard := pool.ShardStats{SingleHits: 3, HeadHits: 1, Misses: 4, Evictions: 2, Idle: 5}
+	b := staticSource{Shards: []pool.ShardStats{shard}, Total: shard, Live: 7, Idle: 5, InUse: 2, Discarded: 1,
+		CleanupRuns: 2, CleanupTime: 3 * time.Second, LastCleanup: time.Second}
+	a := staticSource{Shards: []pool.ShardStats{{}}}
+	if err := exp.Register(`b"\`, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Register("a", a); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	n, err := exp.WriteTo(&sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(sb.Len()) {
+		t.Errorf("WriteTo() = %d bytes, wrote %d", n, sb.Len())
+	}
+	text := sb.String()
+
+	for _, want := range []string{
+		`genpool_hits_total{pool="b\"\\",shard="0",slot="single"} 3`,
+		`genpool_hits_total{pool="b\"\\",shard="0",slot="head"} 1`,
+		`genpool_allocations_total{pool="b\"\\",shard="0"} 4`,
+		`genpool_evictions_total{pool="b\"\\",shard="0"} 2`,
+		`genpool_idle_objects{pool="b\"\\",shard="0"} 5`,
+		`genpool_hit_ratio{pool="b\"\\"} 0.5`,
+		`genpool_discarded_objects_total{pool="b\"\\"} 1`,
+		`genpool_cleanup_duration_seconds_sum{pool="b\"\\"} 3`,
+		`genpool_cleanup_duration_seconds_count{pool="b\"\\"} 2`,
+		`genpool_cleanup_last_duration_seconds{pool="b\"\\"} 1`,
+		`genpool_hit_ratio{pool="a"} 0`,
+	} {
+		if !strings.Contains(text, want+"\n") {
+			t.Errorf("output missing %q", want)
+		}
+	}
+
+	if strings.Count(text, "# TYPE genpool_live_objects") != 1 {
+		t.Error("each family should have exactly one TYPE line")
+	}
+	if strings.Index(text, `genpool_live_objects{pool="a"}`) > strings.Index(text, `genpool_live_objects{pool="b`) {
+		t.Error("pools should be ordered by name within a family")
+	}
+}
+
+// TestRegister covers the registration errors and Unregister.
+func TestRegister(t *testing.T) {
+	exp := New()
+	src := staticSource{}
+
+	if err := exp.Register("", src); !errors.Is(err, ErrEmptyName) {
+		t.Errorf("Register(\"\") error = %v, want ErrEmptyName", err)
+	}
+	if err := exp.Register("p", src); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Register("p", src); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("Register() duplicate error = %v, want ErrDuplicateName", err)
+	}
+	if !exp.Unregister("p") {
+		t.Error("Unregister() = false, want true for a registered pool")
+	}
+	if exp.Unregister("p") {
+		t.Error("Unregister() = true, want false for an unknown pool")
+	}
+
+	rec := httptest.NewRecorder()
+	exp.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+	if strings.Contains(rec.Body.String(), `pool="p"`) {
+		t.Error("unregistered pool should not be exported")
+	}
+}
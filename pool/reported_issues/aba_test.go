@@ -0,0 +1,112 @@
+// Regression test for the ABA hazard on Shard.Head: a Get that loaded head X and
+// X.next could CAS a stale next back into the list after X was popped, reused and
+// pushed again by other goroutines, handing the same object to two callers or
+// resurrecting an evicted one.
+
+package reported_issues
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AlexsanderHamir/GenPool/pool"
+)
+
+// leaseObject records who holds it so a double handout or reuse after eviction is observable.
+type leaseObject struct {
+	leased    atomic.Bool
+	destroyed atomic.Bool
+	pool.Fields[leaseObject]
+}
+
+// TestHeadLinearizability hammers a few shards with Get/Put, work stealing and aggressive
+// cleanup. Every Get must return an object no one else holds and that was never evicted,
+// and when all goroutines are done the pool's accounting must match the objects seen.
+// Run with: go test -race -run TestHeadLinearizability
+func TestHeadLinearizability(t *testing.T) {
+	const rounds = 5
+	for round := range rounds {
+		t.Run(fmt.Sprintf("Round%d", round+1), func(t *testing.T) {
+			runHeadLinearizabilityRound(t)
+		})
+	}
+}
+
+func runHeadLinearizabilityRound(t *testing.T) {
+	const workers = 16
+	const opsPerWorker = 5_000
+	const maxHeld = 4
+
+	var allocated, destroyed atomic.Int64
+	recorder := newStageContractRecorder(10)
+
+	cfg := pool.Config[leaseObject, *leaseObject]{
+		NumShards: 2,
+		Allocator: func() *leaseObject {
+			allocated.Add(1)
+			return &leaseObject{}
+		},
+		Cleaner: func(*leaseObject) {},
+		Destroyer: func(obj *leaseObject) {
+			if obj.leased.Load() {
+				recorder.record("destroyed an object that is leased")
+			}
+			if !obj.destroyed.CompareAndSwap(false, true) {
+				recorder.record("destroyed an object twice")
+			}
+			destroyed.Add(1)
+		},
+		Cleanup: pool.CleanupPolicy{Enabled: true, Interval: time.Millisecond, MinUsageCount: 2},
+		Steal:   pool.StealPolicy{Enable: true, Batch: 2},
+	}
+	p, err := pool.NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			held := make([]*leaseObject, 0, maxHeld)
+			for i := range opsPerWorker {
+				obj := p.Get()
+				if obj.destroyed.Load() {
+					recorder.record("Get() returned an evicted object")
+				}
+				if !obj.leased.CompareAndSwap(false, true) {
+					recorder.record("Get() returned an object that is already leased")
+				}
+				held = append(held, obj)
+
+				// Hold a varying number of objects so Head sees deep pops and pushes.
+				if len(held) == maxHeld || i%3 == 0 {
+					for _, h := range held {
+						h.leased.Store(false)
+						p.Put(h)
+					}
+					held = held[:0]
+				}
+			}
+			for _, h := range held {
+				h.leased.Store(false)
+				p.Put(h)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := p.Stats()
+	live := allocated.Load() - destroyed.Load()
+	if stats.Live != live || stats.Idle != live || stats.InUse != 0 {
+		t.Errorf("accounting after quiesce: live=%d idle=%d in-use=%d, want live=idle=%d in-use=0",
+			stats.Live, stats.Idle, stats.InUse, live)
+	}
+
+	p.Close()
+	recorder.report(t)
+}
@@ -0,0 +1,103 @@
+package pool
+
+import (
+	"runtime"
+	"slices"
+	"time"
+)
+
+// startResharder starts the goroutine that keeps the shard count equal to GOMAXPROCS.
+func (p *ShardedPool[T, P]) startResharder() {
+	p.reshardKick = make(chan struct{}, 1)
+
+	p.cleanWg.Add(1)
+	go func() {
+		defer p.cleanWg.Done()
+		ticker := time.NewTicker(p.cfg.Reshard.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-p.reshardKick:
+			case <-p.stopClean:
+				return
+			}
+			p.reshard(runtime.GOMAXPROCS(0))
+		}
+	}()
+}
+
+// kickResharder asks the resharder for an immediate check without blocking Get.
+func (p *ShardedPool[T, P]) kickResharder() {
+	select {
+	case p.reshardKick <- struct{}{}:
+	default:
+	}
+}
+
+// reshard changes the live shard count to n. New shards are appended (or revived from
+// earlier shrinks); removed shards are marked retired and their idle objects are moved
+// to live shards with rewritten shard indexes. Objects still checked out keep their old
+// index until Put, which re-homes any index beyond the live set.
+func (p *ShardedPool[T, P]) reshard(n int) {
+	p.reshardMu.Lock()
+	defer p.reshardMu.Unlock()
+
+	if n <= 0 || n == len(p.shardList()) || p.closed.Load() {
+		return
+	}
+
+	for i := len(p.allShards); i < n; i++ {
+		p.allShards = append(p.allShards, newShard[T, P](i, p.cfg.TrackStats))
+	}
+	all := p.allShards
+	p.everyShards.Store(&all)
+	live := slices.Clone(p.allShards[:n])
+	for _, shard := range live {
+		shard.retired.Store(false)
+	}
+	for _, shard := range p.allShards[n:] {
+		shard.retired.Store(true)
+	}
+	p.shards.Store(&live)
+
+	for _, shard := range p.allShards[n:] {
+		p.migrate(shard)
+	}
+}
+
+// migrate moves every idle object out of a retired shard into the live set, spreading
+// them round-robin. Safe to run concurrently with Get/Put and with itself.
+func (p *ShardedPool[T, P]) migrate(from *Shard[T, P]) {
+	current := p.tryTakeOwnership(from)
+	if current == nil {
+		return
+	}
+
+	live := p.shardList()
+	for moved := 0; current != nil; moved++ {
+		next := current.GetNext()
+		idx := moved % len(live)
+		current.SetShardIndex(idx)
+		from.idle.Add(-1) // before the push, so the object is never counted idle twice
+		live[idx].push(current)
+		current = next
+	}
+	// live is a snapshot; a concurrent reshard may have retired some of its shards
+	// after their own migration ran.
+	for _, shard := range live {
+		p.settle(shard)
+	}
+}
+
+// settle runs after objects were pushed into shard: it hands them to parked GetContext
+// callers and, if resharding retired shard meanwhile, moves the rest to the live set.
+// Retiring stores the flag before migrating, so either that migration sees the pushed
+// objects or this sees the flag.
+func (p *ShardedPool[T, P]) settle(shard *Shard[T, P]) {
+	p.feed(shard)
+	if shard.retired.Load() {
+		p.migrate(shard)
+	}
+}
@@ -0,0 +1,207 @@
+// Pool statistics. Object counts are always kept: each shard counts the objects on its
+// Head list, and Single is counted by looking at it, so a Get/Put cycle through Single
+// costs no extra atomics. The activity counters (hits, misses, Puts, ...) cost an atomic
+// add each on the Get/Put path and are only kept with Config.TrackStats. Both live
+// per shard so Get/Put only touch shard-local cache lines; Stats sums them on demand.
+package pool
+
+import (
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// shardCounters are the per-shard activity counters behind ShardStats, allocated only
+// with Config.TrackStats.
+type shardCounters struct {
+	singleHits    atomic.Int64
+	headHits      atomic.Int64
+	misses        atomic.Int64
+	puts          atomic.Int64
+	capRejections atomic.Int64
+	evictions     atomic.Int64
+	casRetries    atomic.Int64
+	steals        atomic.Int64
+	stolen        atomic.Int64
+
+	_ [128 - 9*unsafe.Sizeof(atomic.Int64{})]byte // keep shards' counters off each other's cache lines
+}
+
+// addRetries records n failed CAS attempts; n is accumulated locally so the retry
+// loops pay for at most one atomic add. c may be nil.
+func (c *shardCounters) addRetries(n int64) {
+	if c != nil && n > 0 {
+		c.casRetries.Add(n)
+	}
+}
+
+// snapshot copies the counters; a nil c (TrackStats off) reads as zero.
+func (c *shardCounters) snapshot() ShardStats {
+	if c == nil {
+		return ShardStats{}
+	}
+	return ShardStats{
+		SingleHits:    c.singleHits.Load(),
+		HeadHits:      c.headHits.Load(),
+		Misses:        c.misses.Load(),
+		Puts:          c.puts.Load(),
+		CapRejections: c.capRejections.Load(),
+		Evictions:     c.evictions.Load(),
+		CASRetries:    c.casRetries.Load(),
+		Steals:        c.steals.Load(),
+		Stolen:        c.stolen.Load(),
+	}
+}
+
+// idleLen returns the number of idle objects in the shard: the Head list plus Single.
+func (s *Shard[T, P]) idleLen() int64 {
+	n := s.idle.Load()
+	if s.Single.Load() != nil {
+		n++
+	}
+	return n
+}
+
+// cleanupCounters track background cleanup passes.
+type cleanupCounters struct {
+	runs  atomic.Int64
+	total atomic.Int64 // nanoseconds
+	last  atomic.Int64 // nanoseconds
+}
+
+func (c *cleanupCounters) record(d time.Duration) {
+	c.runs.Add(1)
+	c.total.Add(int64(d))
+	c.last.Store(int64(d))
+}
+
+// ShardStats is a point-in-time copy of one shard's counters. All fields except Idle
+// are cumulative since the pool was created, and stay zero unless Config.TrackStats
+// is set.
+type ShardStats struct {
+	SingleHits    int64 // Get served from the Single slot
+	HeadHits      int64 // Get served from the Head list
+	Misses        int64 // Get that called the Allocator
+	Puts          int64 // objects returned with Put
+	CapRejections int64 // Get that found nothing because MaxPoolSize was reached
+	Evictions     int64 // objects dropped by cleanup, MaxLifetime, the Validator or MaxIdle
+	CASRetries    int64 // failed compare-and-swaps on Single/Head
+	Steals        int64 // Get served by stealing from a sibling shard
+	Stolen        int64 // objects taken from sibling shards, including batch extras
+	Idle          int64 // objects currently sitting in the shard; always kept
+}
+
+// Hits is the number of Get calls served by a pooled object, stolen ones included.
+func (s ShardStats) Hits() int64 {
+	return s.SingleHits + s.HeadHits + s.Steals
+}
+
+// Gets is the number of Get calls: hits, allocations and cap rejections.
+func (s ShardStats) Gets() int64 {
+	return s.Hits() + s.Misses + s.CapRejections
+}
+
+// HitRatio is Hits/Gets, or 0 before the first Get.
+func (s ShardStats) HitRatio() float64 {
+	gets := s.Gets()
+	if gets == 0 {
+		return 0
+	}
+	return float64(s.Hits()) / float64(gets)
+}
+
+func (s *ShardStats) add(o ShardStats) {
+	s.SingleHits += o.SingleHits
+	s.HeadHits += o.HeadHits
+	s.Misses += o.Misses
+	s.Puts += o.Puts
+	s.CapRejections += o.CapRejections
+	s.Evictions += o.Evictions
+	s.CASRetries += o.CASRetries
+	s.Steals += o.Steals
+	s.Stolen += o.Stolen
+	s.Idle += o.Idle
+}
+
+// Stats is a snapshot of pool behavior. Counters are read one by one without a
+// global lock, so under concurrent use the totals are approximate.
+type Stats struct {
+	// Shards holds one entry per live shard, indexed like ShardedPool.CurrentShards.
+	Shards []ShardStats
+	// Total is the sum over every shard, including shards retired by resharding, so
+	// cumulative counters never go backwards.
+	Total ShardStats
+	// Live is the number of objects the pool accounts for (CurrentPoolLength).
+	Live int64
+	// Idle is the number of objects sitting in shards, ready for Get.
+	Idle int64
+	// InUse is Live minus Idle: objects handed out and not yet returned, discarded or
+	// recovered.
+	InUse int64
+	// Discarded is the number of objects given up with Discard.
+	Discarded int64
+	// Dropped is the number of checked-out objects garbage-collected without Put or
+	// Discard and recovered by Config.RecoverDropped.
+	Dropped int64
+
+	// CleanupRuns is the number of completed cleanup passes.
+	CleanupRuns int64
+	// CleanupTime is the total time spent in cleanup passes.
+	CleanupTime time.Duration
+	// LastCleanup is the duration of the most recent cleanup pass.
+	LastCleanup time.Duration
+}
+
+// IdleCount returns the number of objects sitting in shards, ready for Get. Like the
+// other counts it sums per-shard counters without a lock, so it is approximate while
+// Get and Put run concurrently.
+func (p *ShardedPool[T, P]) IdleCount() int64 {
+	var idle int64
+	for _, shard := range p.everyShard() {
+		idle += shard.idleLen()
+	}
+	return idle
+}
+
+// InUseCount returns the number of objects handed out and not yet returned with Put,
+// given up with Discard, or recovered after being dropped: TotalCount minus
+// IdleCount. Objects leave the idle count before they are handed out and join it only
+// once they are back in a shard, so an object in a caller's hands is never missed.
+// There is no in-use counter of its own, so Get and Put pay nothing to keep it.
+func (p *ShardedPool[T, P]) InUseCount() int64 {
+	return max(p.TotalCount()-p.IdleCount(), 0)
+}
+
+// TotalCount returns the number of live objects, idle or in use: everything allocated
+// and not yet destroyed. It is the counter GrowthPolicy.MaxPoolSize caps, kept exact
+// by CurrentPoolLength.
+func (p *ShardedPool[T, P]) TotalCount() int64 {
+	return p.CurrentPoolLength.Load()
+}
+
+// Stats returns a snapshot of the per-shard and aggregate counters.
+func (p *ShardedPool[T, P]) Stats() Stats {
+	p.reshardMu.RLock()
+	live := p.shardList()
+	stats := Stats{Shards: make([]ShardStats, len(live))}
+	for i, shard := range p.allShards {
+		snap := shard.counters.snapshot()
+		snap.Idle = shard.idleLen()
+		if i < len(live) {
+			stats.Shards[i] = snap
+		}
+		stats.Total.add(snap)
+	}
+	p.reshardMu.RUnlock()
+
+	stats.Live = p.TotalCount()
+	stats.Idle = stats.Total.Idle
+	stats.InUse = max(stats.Live-stats.Idle, 0)
+	stats.Discarded = p.discarded.Load()
+	stats.Dropped = p.dropped.Load()
+
+	stats.CleanupRuns = p.cleanupStats.runs.Load()
+	stats.CleanupTime = time.Duration(p.cleanupStats.total.Load())
+	stats.LastCleanup = time.Duration(p.cleanupStats.last.Load())
+	return stats
+}
@@ -0,0 +1,44 @@
+package pool
+
+// steal scans up to Steal.Shards sibling shards after shardID and takes up to
+// Steal.Batch idle objects from the first one that has any. One object is returned
+// to the caller; the rest are moved to the thief's shard. Every stolen object is
+// re-homed to shardID so later Puts return it where the demand is.
+func (p *ShardedPool[T, P]) steal(thief *Shard[T, P], shardID int) P {
+	shards := p.shardList()
+	numShards := len(shards)
+	scan := p.cfg.Steal.Shards
+	if scan <= 0 || scan >= numShards {
+		scan = numShards - 1
+	}
+	batch := max(p.cfg.Steal.Batch, 1)
+
+	for i := 1; i <= scan; i++ {
+		victim := shards[(shardID+i)%numShards]
+		obj, _ := victim.pop()
+		if obj == nil {
+			continue
+		}
+		obj.SetShardIndex(shardID)
+
+		stolen := int64(1)
+		for ; stolen < int64(batch); stolen++ {
+			extra, _ := victim.pop()
+			if extra == nil {
+				break
+			}
+			extra.SetShardIndex(shardID)
+			thief.push(extra)
+		}
+		if stolen > 1 {
+			p.settle(thief)
+		}
+
+		if c := thief.counters; c != nil {
+			c.steals.Add(1)
+			c.stolen.Add(stolen)
+		}
+		return obj
+	}
+	return nil
+}
@@ -1,12 +1,16 @@
 package pool
 
 import (
+	"context"
 	"errors"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+	"unsafe"
 )
 
 // TestObject is a simple struct we'll use for testing.
@@ -139,6 +143,42 @@ func TestFields(t *testing.T) {
 	}
 }
 
+// TestShardSize ensures the padding keeps each Shard on its own 128-byte block.
+func TestShardSize(t *testing.T) {
+	if size := unsafe.Sizeof(Shard[TestObject, *TestObject]{}); size != 128 {
+		t.Errorf("Shard size = %d, want 128", size)
+	}
+}
+
+// TestMetaOnDemand ensures the per-object state behind Meta is only allocated when a
+// feature needs it, and that Fields stays four words.
+func TestMetaOnDemand(t *testing.T) {
+	if size, want := unsafe.Sizeof(Fields[TestObject]{}), 4*unsafe.Sizeof(uintptr(0)); size != want {
+		t.Errorf("Fields size = %d, want %d", size, want)
+	}
+
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.Cleanup.Enabled = false
+	plain, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Close()
+	if obj := plain.Get(); obj.PoolMeta().state != nil {
+		t.Error("Get() without optional features should not allocate Meta state")
+	}
+
+	cfg.MaxLifetime = time.Hour
+	aging, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aging.Close()
+	if obj := aging.Get(); obj.PoolMeta().state == nil || obj.PoolMeta().state.createdAt == 0 {
+		t.Error("Get() with MaxLifetime should allocate Meta state stamped with the creation time")
+	}
+}
+
 // TestDefaultConfig tests the DefaultConfig function
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig(testAllocator, testCleaner)
@@ -367,12 +407,14 @@ func TestClear(t *testing.T) {
 	// Clear the pool
 	pool.clear()
 
-	// Verify all shards are empty (Single field is not cleared by clear() method)
+	// Verify all shards are empty, Single slots included
 	for i, shard := range pool.Shards {
-		if shard.Head.Load() != nil {
+		if shard.Head.Load() != nil || shard.Single.Load() != nil {
 			t.Errorf("clear() shard[%d] not empty", i)
 		}
-		// Note: Single field is not cleared by clear() method as it's a fast path optimization
+	}
+	if pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("clear() CurrentPoolLength = %d, want 0", pool.CurrentPoolLength.Load())
 	}
 }
 
@@ -551,7 +593,7 @@ func TestFilterUsableObjects(t *testing.T) {
 
 	pool.cfg.Cleanup.MinUsageCount = 2
 
-	keptHead, keptTail, _ := pool.filterUsableObjects(obj1)
+	keptHead, keptTail, _ := pool.filterUsableObjects(obj1, 0, noLimit)
 
 	if keptHead == nil {
 		t.Error("filterUsableObjects() should return kept objects")
@@ -775,7 +817,7 @@ func TestFilterUsableObjectsEdgeCases(t *testing.T) {
 	// Set MinUsageCount to 3, so all objects should be discarded
 	pool.cfg.Cleanup.MinUsageCount = 3
 
-	keptHead, keptTail, _ := pool.filterUsableObjects(obj1)
+	keptHead, keptTail, _ := pool.filterUsableObjects(obj1, 0, noLimit)
 
 	// All objects should be discarded
 	if keptHead != nil {
@@ -828,7 +870,7 @@ func TestFilterUsableObjectsMixed(t *testing.T) {
 	// Set MinUsageCount to 3, so only obj2 and obj4 should be kept
 	pool.cfg.Cleanup.MinUsageCount = 3
 
-	keptHead, keptTail, _ := pool.filterUsableObjects(obj1)
+	keptHead, keptTail, _ := pool.filterUsableObjects(obj1, 0, noLimit)
 
 	// Should keep obj2 and obj4
 	if keptHead != obj2 {
@@ -935,12 +977,11 @@ func TestClearRaceCondition(t *testing.T) {
 	// Clear again to ensure all objects added during the race are also cleared
 	pool.clear()
 
-	// Verify all shards are empty after clear (Single field is not cleared by clear() method)
+	// Verify all shards are empty after clear, Single slots included
 	for i, shard := range pool.Shards {
-		if shard.Head.Load() != nil {
+		if shard.Head.Load() != nil || shard.Single.Load() != nil {
 			t.Errorf("clear() shard[%d] not empty after race condition test", i)
 		}
-		// Note: Single field is not cleared by clear() method as it's a fast path optimization
 	}
 }
 
@@ -1192,27 +1233,2078 @@ func TestCurrentPoolLength(t *testing.T) {
 	}
 
 	// Get it again - length should remain the same (reusing existing object)
-	_ = pool.Get()
+	obj = pool.Get()
 	if pool.CurrentPoolLength.Load() != 1 {
 		t.Error("CurrentPoolLength should remain 1 after reusing object")
 	}
 
-	// Clear the pool - length should decrease
-	// Note: clear() only clears Head field, not Single field, so objects in Single field remain
+	// Put it back and clear the pool - the object sits in Single and must be counted
+	pool.Put(obj)
 	pool.clear()
 
-	// TODO: The clear() method has a limitation - it doesn't clear the Single field
-	// and doesn't update CurrentPoolLength for objects in Single field.
-	// This means CurrentPoolLength may not accurately reflect the pool state after clear().
-	// For now, we'll just verify that the method doesn't panic and that Head fields are cleared.
-
-	// Verify that Head fields are cleared
 	for i, shard := range pool.Shards {
-		if shard.Head.Load() != nil {
-			t.Errorf("clear() shard[%d] head not empty", i)
+		if shard.Head.Load() != nil || shard.Single.Load() != nil {
+			t.Errorf("clear() shard[%d] not empty", i)
+		}
+	}
+	if pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("CurrentPoolLength after clear = %d, want 0", pool.CurrentPoolLength.Load())
+	}
+}
+
+// newCappedPool returns a single-shard pool without cleanup capped at maxSize objects.
+func newCappedPool(t *testing.T, maxSize int64) *ShardedPool[TestObject, *TestObject] {
+	t.Helper()
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: maxSize}
+	cfg.TrackStats = true
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+// TestGetContextWaitsForPut ensures a caller blocked at the cap is woken by Put.
+func TestGetContextWaitsForPut(t *testing.T) {
+	pool := newCappedPool(t, 1)
+	defer pool.Close()
+
+	held := pool.Get()
+	if held == nil {
+		t.Fatal("Get() returned nil below the cap")
+	}
+
+	got := make(chan *TestObject)
+	go func() {
+		obj, err := pool.GetContext(context.Background())
+		if err != nil {
+			t.Errorf("GetContext() error = %v", err)
+		}
+		got <- obj
+	}()
+
+	for pool.waiters.count.Load() == 0 {
+		runtime.Gosched()
+	}
+	pool.Put(held)
+
+	select {
+	case obj := <-got:
+		if obj != held {
+			t.Errorf("GetContext() = %p, want the returned object %p", obj, held)
+		}
+		if obj.GetUsageCount() != 2 {
+			t.Errorf("GetContext() usage count = %d, want 2", obj.GetUsageCount())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetContext() was not woken by Put")
+	}
+
+	if pool.waiters.count.Load() != 0 {
+		t.Errorf("waiters count = %d, want 0", pool.waiters.count.Load())
+	}
+}
+
+// TestGetContextDeadline ensures GetContext gives up with the context error and leaves no waiter behind.
+func TestGetContextDeadline(t *testing.T) {
+	pool := newCappedPool(t, 1)
+	defer pool.Close()
+
+	held := pool.Get()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	obj, err := pool.GetContext(ctx)
+	if obj != nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetContext() = %v, %v; want nil, DeadlineExceeded", obj, err)
+	}
+	if pool.waiters.count.Load() != 0 {
+		t.Errorf("waiters count = %d, want 0", pool.waiters.count.Load())
+	}
+
+	// The object must still go back to the pool rather than to the departed waiter.
+	pool.Put(held)
+	if pool.Get() != held {
+		t.Error("Put() after a cancelled GetContext should pool the object")
+	}
+}
+
+// TestGetContextNoGrowth ensures GetContext never blocks without a growth policy.
+func TestGetContextNoGrowth(t *testing.T) {
+	pool, err := NewPool(testAllocator, testCleaner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	obj, err := pool.GetContext(ctx)
+	if obj == nil || err != nil {
+		t.Errorf("GetContext() = %v, %v; want object, nil", obj, err)
+	}
+}
+
+// TestGetContextConcurrent runs more goroutines than the cap allows; every GetContext
+// must eventually succeed and the cap must never be exceeded.
+func TestGetContextConcurrent(t *testing.T) {
+	const maxSize = 4
+	pool := newCappedPool(t, maxSize)
+	defer pool.Close()
+
+	var inUse, peak atomic.Int64
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				obj, err := pool.GetContext(context.Background())
+				if err != nil {
+					t.Errorf("GetContext() error = %v", err)
+					return
+				}
+				n := inUse.Add(1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				inUse.Add(-1)
+				pool.Put(obj)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > maxSize {
+		t.Errorf("objects in use peaked at %d, want <= %d", peak.Load(), maxSize)
+	}
+	if pool.CurrentPoolLength.Load() > maxSize {
+		t.Errorf("CurrentPoolLength = %d, want <= %d", pool.CurrentPoolLength.Load(), maxSize)
+	}
+}
+
+// TestTryGet checks the sentinel errors reported by TryGet.
+func TestTryGet(t *testing.T) {
+	pool := newCappedPool(t, 1)
+
+	obj, err := pool.TryGet()
+	if obj == nil || err != nil {
+		t.Fatalf("TryGet() = %v, %v; want object, nil", obj, err)
+	}
+
+	if _, err = pool.TryGet(); !errors.Is(err, ErrPoolExhausted) {
+		t.Errorf("TryGet() at cap error = %v, want ErrPoolExhausted", err)
+	}
+
+	pool.Put(obj)
+	if got, err := pool.TryGet(); got != obj || err != nil {
+		t.Errorf("TryGet() after Put = %v, %v; want pooled object, nil", got, err)
+	}
+
+	pool.Close()
+	if _, err = pool.TryGet(); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("TryGet() after Close error = %v, want ErrPoolClosed", err)
+	}
+}
+
+// TestGetContextClosed ensures Close releases callers blocked in GetContext.
+func TestGetContextClosed(t *testing.T) {
+	pool := newCappedPool(t, 1)
+	_ = pool.Get()
+
+	errCh := make(chan error)
+	go func() {
+		_, err := pool.GetContext(context.Background())
+		errCh <- err
+	}()
+
+	for pool.waiters.count.Load() == 0 {
+		runtime.Gosched()
+	}
+	pool.Close()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("GetContext() error = %v, want ErrPoolClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close() did not release the blocked GetContext")
+	}
+}
+
+// TestGetContextOtherShard ensures GetContext takes an object idle in another shard
+// instead of parking on its own empty one.
+func TestGetContextOtherShard(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1)) // every Get maps to shard 0
+
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 2
+	cfg.Cleanup.Enabled = false
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	held := pool.Get()
+	held.SetShardIndex(1)
+	pool.Put(held)
+	if pool.Shards[1].idleLen() != 1 {
+		t.Fatal("object should be idle in shard 1")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	obj, err := pool.GetContext(ctx)
+	if err != nil || obj != held {
+		t.Errorf("GetContext() = %p, %v; want the object idle in shard 1 %p", obj, err, held)
+	}
+}
+
+// TestGetContextCleanupEviction ensures a caller parked at the cap gets a fresh object
+// when cleanup evicts an idle one.
+func TestGetContextCleanupEviction(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Cleanup.MinUsageCount = 5
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	held := pool.Get()
+	got := make(chan *TestObject)
+	go func() {
+		obj, _ := pool.GetContext(context.Background())
+		got <- obj
+	}()
+	for pool.waiters.count.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	// Park the object behind the waiter's back, as if it was pooled while the waiter
+	// was scanning, and let cleanup evict it for its low usage count.
+	pool.Shards[0].push(held)
+	pool.cleanupShard(pool.Shards[0])
+	select {
+	case obj := <-got:
+		if obj == nil || obj == held {
+			t.Error("GetContext() should receive a fresh object after cleanup evicted the idle one")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetContext() was not woken after cleanup freed budget")
+	}
+	if live := pool.CurrentPoolLength.Load(); live != 1 {
+		t.Errorf("CurrentPoolLength = %d, want 1", live)
+	}
+}
+
+// TestGetContextValidatorRetire ensures a caller parked at the cap gets a fresh object
+// when Get retires an idle one the Validator rejects.
+func TestGetContextValidatorRetire(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+	cfg.Validator = func(obj *TestObject) bool { return obj.Value != "broken" }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	held := pool.Get()
+	got := make(chan *TestObject)
+	go func() {
+		obj, _ := pool.GetContext(context.Background())
+		got <- obj
+	}()
+	for pool.waiters.count.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	held.Value = "broken"
+	pool.Shards[0].push(held)
+	if obj := pool.Get(); obj != nil {
+		t.Errorf("Get() = %p, want nil: the budget freed by the retired object belongs to the waiter", obj)
+	}
+	select {
+	case obj := <-got:
+		if obj == nil || obj == held {
+			t.Error("GetContext() should receive a fresh object after the invalid one was retired")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetContext() was not woken after Get retired an invalid object")
+	}
+}
+
+// TestGetContextValidatesHandoff ensures an object passed straight to a parked caller,
+// by Put or by feed, goes through the Validator like one popped from a shard.
+func TestGetContextValidatesHandoff(t *testing.T) {
+	for _, viaFeed := range []bool{false, true} {
+		var rejected atomic.Pointer[TestObject]
+		cfg := DefaultConfig(testAllocator, testCleaner)
+		cfg.NumShards = 1
+		cfg.Cleanup.Enabled = false
+		cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+		cfg.Validator = func(obj *TestObject) bool { return obj != rejected.Load() }
+		pool, err := NewPoolWithConfig(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		held := pool.Get()
+		rejected.Store(held)
+		got := make(chan *TestObject)
+		go func() {
+			obj, _ := pool.GetContext(context.Background())
+			got <- obj
+		}()
+		for pool.waiters.count.Load() == 0 {
+			runtime.Gosched()
+		}
+
+		if viaFeed {
+			pool.Shards[0].push(held)
+			pool.feed(pool.Shards[0])
+		} else {
+			pool.Put(held)
+		}
+		select {
+		case obj := <-got:
+			if obj == nil || obj == held {
+				t.Errorf("viaFeed=%v: GetContext() = %p, want a fresh object in place of the rejected one", viaFeed, obj)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("viaFeed=%v: GetContext() was not woken", viaFeed)
 		}
+		if n := pool.CurrentPoolLength.Load(); n != 1 {
+			t.Errorf("viaFeed=%v: CurrentPoolLength = %d, want 1", viaFeed, n)
+		}
+		pool.Close()
+	}
+}
+
+// TestStats walks a single-shard pool through each counted path and checks the snapshot.
+func TestStats(t *testing.T) {
+	pool := newCappedPool(t, 2)
+	defer pool.Close()
+
+	o1, o2 := pool.Get(), pool.Get() // 2 misses
+	_ = pool.Get()                   // cap rejection
+	pool.Put(o1)                     // Single
+	pool.Put(o2)                     // Head
+
+	stats := pool.Stats()
+	if len(stats.Shards) != 1 {
+		t.Fatalf("Stats() shards = %d, want 1", len(stats.Shards))
+	}
+	if stats.Total.Misses != 2 || stats.Total.CapRejections != 1 || stats.Total.Puts != 2 {
+		t.Errorf("Stats() = %+v, want 2 misses, 1 cap rejection, 2 puts", stats.Total)
+	}
+	if stats.Live != 2 || stats.Idle != 2 || stats.InUse != 0 {
+		t.Errorf("Stats() live/idle/in-use = %d/%d/%d, want 2/2/0", stats.Live, stats.Idle, stats.InUse)
+	}
+
+	_ = pool.Get() // Single hit
+	_ = pool.Get() // Head hit
+
+	stats = pool.Stats()
+	if stats.Total.SingleHits != 1 || stats.Total.HeadHits != 1 {
+		t.Errorf("Stats() single/head hits = %d/%d, want 1/1", stats.Total.SingleHits, stats.Total.HeadHits)
+	}
+	if stats.Idle != 0 || stats.InUse != 2 {
+		t.Errorf("Stats() idle/in-use = %d/%d, want 0/2", stats.Idle, stats.InUse)
+	}
+	if stats.Shards[0] != stats.Total {
+		t.Errorf("Stats() single shard %+v should equal total %+v", stats.Shards[0], stats.Total)
+	}
+}
+
+// TestStatsUntracked checks that object counts are kept without TrackStats while the
+// activity counters stay zero.
+func TestStatsUntracked(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	objs := []*TestObject{pool.Get(), pool.Get(), pool.Get()}
+	pool.Put(objs[0])
+	pool.Put(objs[1])
+	_ = pool.Get()
+
+	stats := pool.Stats()
+	if stats.Total != (ShardStats{Idle: 1}) {
+		t.Errorf("Stats() total = %+v, want only Idle 1", stats.Total)
+	}
+	if stats.Live != 3 || stats.Idle != 1 || stats.InUse != 2 {
+		t.Errorf("Stats() live/idle/in-use = %d/%d/%d, want 3/1/2", stats.Live, stats.Idle, stats.InUse)
+	}
+	if pool.IdleCount() != 1 || pool.InUseCount() != 2 {
+		t.Errorf("IdleCount/InUseCount = %d/%d, want 1/2", pool.IdleCount(), pool.InUseCount())
+	}
+}
+
+// TestStatsEvictions checks that cleanup evictions are counted and leave the pool idle count consistent.
+func TestStatsEvictions(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Cleanup.MinUsageCount = 2
+	cfg.TrackStats = true
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	objs := []*TestObject{pool.Get(), pool.Get(), pool.Get()}
+	for _, obj := range objs {
+		pool.Put(obj) // usage 1: first goes to Single, the rest to Head
+	}
+	objs[0] = pool.Get() // take the Single one back and raise its usage to 2
+	pool.Put(objs[0])
+	pool.cleanupShard(pool.Shards[0])
+
+	stats := pool.Stats()
+	if stats.Total.Evictions != 2 {
+		t.Errorf("Stats() evictions = %d, want 2", stats.Total.Evictions)
+	}
+	if stats.Idle != 1 || stats.Live != 1 {
+		t.Errorf("Stats() idle/live = %d/%d, want 1/1", stats.Idle, stats.Live)
+	}
+}
+
+// TestHooks checks that every lifecycle hook fires with the right shard and reason.
+func TestHooks(t *testing.T) {
+	var allocated, got, put int
+	evicted := map[EvictReason]int{}
+
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Cleanup.MinUsageCount = 2
+	cfg.Hooks = Hooks[TestObject]{
+		OnAllocate: func(obj *TestObject, shard int) {
+			if obj.ID != 1 || shard != 0 {
+				t.Errorf("OnAllocate(%+v, %d), want fresh object on shard 0", obj, shard)
+			}
+			allocated++
+		},
+		OnGet: func(_ *TestObject, _ int) { got++ },
+		OnPut: func(obj *TestObject, _ int) {
+			if obj.Value == "" {
+				t.Error("OnPut should run before the Cleaner")
+			}
+			put++
+		},
+		OnEvict: func(_ *TestObject, _ int, reason EvictReason) { evicted[reason]++ },
+	}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
 	}
+	defer pool.Close()
 
-	// Note: CurrentPoolLength may not be accurate due to Single field limitation
-	// This is a known limitation of the current clear() implementation
+	o1, o2, o3 := pool.Get(), pool.Get(), pool.Get()
+	pool.Put(o1)
+	o1 = pool.Get()
+	o1.Value = "again"
+	pool.Put(o1) // usage 2: Single
+	pool.Put(o2) // usage 1: Head, evicted by cleanup
+	pool.Put(o3) // usage 1: Head, evicted by cleanup
+	pool.cleanupShard(pool.Shards[0])
+
+	if allocated != 3 || got != 4 || put != 4 {
+		t.Errorf("hooks allocate/get/put = %d/%d/%d, want 3/4/4", allocated, got, put)
+	}
+	if evicted[EvictCleanup] != 2 {
+		t.Errorf("OnEvict(cleanup) = %d, want 2", evicted[EvictCleanup])
+	}
+
+	a, b := pool.Get(), pool.Get() // a from Single, b allocated
+	a.Value = "again"
+	pool.Put(a) // Single
+	pool.Put(b) // Head
+	pool.clear()
+	if evicted[EvictClose] != 2 {
+		t.Errorf("OnEvict(close) = %d, want 2", evicted[EvictClose])
+	}
+}
+
+// TestDestroyer ensures the Destroyer runs exactly once for each dropped object and
+// that clear no longer re-runs the Cleaner.
+func TestDestroyer(t *testing.T) {
+	destroyed := map[*TestObject]int{}
+	var cleaned int
+
+	cfg := DefaultConfig(testAllocator, func(obj *TestObject) {
+		cleaned++
+		testCleaner(obj)
+	})
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Cleanup.MinUsageCount = 2
+	cfg.Destroyer = func(obj *TestObject) { destroyed[obj]++ }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	o1, o2, o3 := pool.Get(), pool.Get(), pool.Get()
+	pool.Put(o1) // Single, usage 1
+	pool.Put(o2) // Head, usage 1
+	pool.Put(o3) // Head, usage 1
+	if len(destroyed) != 0 {
+		t.Fatal("Put() must not call the Destroyer")
+	}
+
+	pool.cleanupShard(pool.Shards[0])
+	if destroyed[o1] != 1 || destroyed[o2] != 1 || destroyed[o3] != 1 {
+		t.Errorf("cleanup destroyed o1=%d o2=%d o3=%d times, want 1 each", destroyed[o1], destroyed[o2], destroyed[o3])
+	}
+
+	o4 := pool.Get()
+	o5 := pool.Get()
+	pool.Put(o4)
+	pool.Put(o5)
+	cleanedBefore := cleaned
+	pool.clear()
+	if destroyed[o5] != 1 {
+		t.Errorf("clear() destroyed o5 %d times, want 1", destroyed[o5])
+	}
+	if cleaned != cleanedBefore {
+		t.Error("clear() should not re-run the Cleaner")
+	}
+	for obj, n := range destroyed {
+		if n != 1 {
+			t.Errorf("object %p destroyed %d times, want 1", obj, n)
+		}
+	}
+}
+
+// TestSteal checks batch stealing, re-homing and the steal counters.
+func TestSteal(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 3
+	cfg.Cleanup.Enabled = false
+	cfg.Steal = StealPolicy{Enable: true, Shards: 2, Batch: 3}
+	cfg.TrackStats = true
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	victim := pool.Shards[2]
+	for range 4 {
+		obj := testAllocator()
+		obj.SetShardIndex(2)
+		victim.push(obj)
+	}
+
+	thief := pool.Shards[0]
+	obj := pool.steal(thief, 0)
+	if obj == nil {
+		t.Fatal("steal() returned nil while a sibling had idle objects")
+	}
+	if obj.GetShardIndex() != 0 {
+		t.Errorf("stolen object shard index = %d, want 0", obj.GetShardIndex())
+	}
+
+	stats := pool.Stats()
+	if stats.Shards[0].Steals != 1 || stats.Shards[0].Stolen != 3 {
+		t.Errorf("thief steals/stolen = %d/%d, want 1/3", stats.Shards[0].Steals, stats.Shards[0].Stolen)
+	}
+	if stats.Shards[0].Idle != 2 || stats.Shards[2].Idle != 1 {
+		t.Errorf("idle thief/victim = %d/%d, want 2/1", stats.Shards[0].Idle, stats.Shards[2].Idle)
+	}
+	for {
+		extra, _ := thief.pop()
+		if extra == nil {
+			break
+		}
+		if extra.GetShardIndex() != 0 {
+			t.Errorf("batch extra shard index = %d, want 0", extra.GetShardIndex())
+		}
+	}
+
+	if pool.steal(pool.Shards[1], 1) == nil {
+		t.Error("steal() should reach shard 2 from shard 1")
+	}
+	if pool.steal(pool.Shards[1], 1) != nil {
+		t.Error("steal() should return nil when every sibling is empty")
+	}
+}
+
+// TestStealBeforeCap ensures Get steals idle objects instead of returning nil at the cap.
+func TestStealBeforeCap(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 4
+	cfg.Cleanup.Enabled = false
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+	cfg.Steal = StealPolicy{Enable: true}
+	cfg.TrackStats = true
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	obj := pool.Get()
+	for i := range pool.Shards {
+		obj.SetShardIndex(i)
+		pool.Put(obj)
+		if got := pool.Get(); got != obj {
+			t.Fatalf("Get() with the only object idle in shard %d = %v, want it", i, got)
+		}
+	}
+	if misses := pool.Stats().Total.Misses; misses != 1 {
+		t.Errorf("allocations = %d, want 1", misses)
+	}
+}
+
+// TestValidateConfigSteal rejects negative steal settings.
+func TestValidateConfigSteal(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.Steal = StealPolicy{Enable: true, Batch: -1}
+	if err := validateConfig(cfg); err == nil {
+		t.Error("validateConfig() should reject a negative steal batch")
+	}
+}
+
+// TestCleanupShardSingle ensures cleanup evicts or keeps the Single slot like any other idle object.
+func TestCleanupShardSingle(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Cleanup.MinUsageCount = 2
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	shard := pool.Shards[0]
+
+	// Underused object in Single is evicted and uncounted.
+	cold := pool.Get()
+	pool.Put(cold)
+	pool.cleanupShard(shard)
+	if shard.Single.Load() != nil || shard.Head.Load() != nil {
+		t.Error("cleanupShard() should evict an underused object from Single")
+	}
+	if pool.CurrentPoolLength.Load() != 0 || pool.Stats().Idle != 0 {
+		t.Errorf("after eviction live/idle = %d/%d, want 0/0", pool.CurrentPoolLength.Load(), pool.Stats().Idle)
+	}
+
+	// Hot object in Single survives with its usage reset.
+	hot := pool.Get()
+	hot.IncrementUsage()
+	pool.Put(hot)
+	pool.cleanupShard(shard)
+	if got := pool.Get(); got != hot {
+		t.Fatal("cleanupShard() should keep a hot object from Single")
+	}
+	if hot.GetUsageCount() != 1 {
+		t.Errorf("kept object usage = %d, want 1 (reset, then one Get)", hot.GetUsageCount())
+	}
+	if pool.CurrentPoolLength.Load() != 1 || pool.Stats().Idle != 0 {
+		t.Errorf("live/idle = %d/%d, want 1/0", pool.CurrentPoolLength.Load(), pool.Stats().Idle)
+	}
+}
+
+// TestCloseIdempotentAndDrains covers double Close, draining without cleanup, and the closed state.
+func TestCloseIdempotentAndDrains(t *testing.T) {
+	destroyed := 0
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup = DefaultCleanupPolicy(GcDisable)
+	cfg.Destroyer = func(*TestObject) { destroyed++ }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idle1, idle2, out := pool.Get(), pool.Get(), pool.Get()
+	pool.Put(idle1) // Single
+	pool.Put(idle2) // Head
+
+	pool.Close()
+	pool.Close() // must not panic
+
+	if destroyed != 2 {
+		t.Errorf("Close() destroyed %d idle objects, want 2", destroyed)
+	}
+	if pool.CurrentPoolLength.Load() != 1 {
+		t.Errorf("CurrentPoolLength after Close = %d, want 1 (the object still out)", pool.CurrentPoolLength.Load())
+	}
+
+	if obj := pool.Get(); obj != nil {
+		t.Error("Get() after Close should return nil")
+	}
+
+	pool.Put(out)
+	if destroyed != 3 {
+		t.Error("Put() after Close should destroy the object")
+	}
+	if pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("CurrentPoolLength after Put on closed pool = %d, want 0", pool.CurrentPoolLength.Load())
+	}
+	if shard := pool.Shards[0]; shard.Single.Load() != nil || shard.Head.Load() != nil {
+		t.Error("Put() after Close must not re-pool the object")
+	}
+}
+
+// TestCloseConcurrentPut races Put against Close; nothing may be left pooled afterwards.
+func TestCloseConcurrentPut(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 2
+	cfg.Cleanup.Enabled = false
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objs := make([]*TestObject, 64)
+	for i := range objs {
+		objs[i] = pool.Get()
+	}
+
+	var wg sync.WaitGroup
+	for _, obj := range objs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.Put(obj)
+		}()
+	}
+	pool.Close()
+	wg.Wait()
+
+	if pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("CurrentPoolLength = %d, want 0", pool.CurrentPoolLength.Load())
+	}
+	for i, shard := range pool.Shards {
+		if shard.Single.Load() != nil || shard.Head.Load() != nil {
+			t.Errorf("shard[%d] still holds objects after Close", i)
+		}
+	}
+}
+
+// TestShutdownWaitsForPut ensures Shutdown blocks new Gets and returns once the last object is back.
+func TestShutdownWaitsForPut(t *testing.T) {
+	destroyed := atomic.Int64{}
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Destroyer = func(*TestObject) { destroyed.Add(1) }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idle, out := pool.Get(), pool.Get()
+	pool.Put(idle)
+
+	done := make(chan error)
+	go func() { done <- pool.Shutdown(context.Background()) }()
+
+	for !pool.closed.Load() {
+		runtime.Gosched()
+	}
+	if _, err = pool.TryGet(); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("TryGet() during Shutdown error = %v, want ErrPoolClosed", err)
+	}
+	select {
+	case <-done:
+		t.Fatal("Shutdown() returned while an object was still out")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	pool.Put(out)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Shutdown() error = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown() did not return after the last Put")
+	}
+	if destroyed.Load() != 2 || pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("destroyed/live = %d/%d, want 2/0", destroyed.Load(), pool.CurrentPoolLength.Load())
+	}
+}
+
+// TestShutdownStopsCleaner ensures Shutdown stops the cleanup goroutine before it waits,
+// so a sweep can't leave the wait loop counting an object that is only in transit.
+func TestShutdownStopsCleaner(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Interval = time.Millisecond
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := pool.Get()
+	done := make(chan error)
+	go func() { done <- pool.Shutdown(context.Background()) }()
+
+	for !pool.closed.Load() {
+		runtime.Gosched()
+	}
+	select {
+	case <-pool.stopClean:
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown() left the cleaner running while waiting")
+	}
+
+	pool.Put(out)
+	if err := <-done; err != nil {
+		t.Errorf("Shutdown() error = %v, want nil", err)
+	}
+}
+
+// TestShutdownRacingGet ensures Shutdown waits for a Get that took an object before
+// the pool closed, and that the Get gives the object up instead of handing it out.
+func TestShutdownRacingGet(t *testing.T) {
+	destroyed := atomic.Int64{}
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Destroyer = func(*TestObject) { destroyed.Add(1) }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Put(pool.Get())
+
+	// A Get that passed its closed check and popped the object, but has not
+	// checked it out yet.
+	taken := pool.popIdle(pool.Shards[0])
+
+	done := make(chan error)
+	go func() { done <- pool.Shutdown(context.Background()) }()
+	for !pool.closed.Load() {
+		runtime.Gosched()
+	}
+	select {
+	case <-done:
+		t.Fatal("Shutdown() returned while a Get was handing out an object")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if obj := pool.handOut(taken); obj != nil {
+		t.Error("handOut() after Shutdown started should destroy the object, not return it")
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Shutdown() error = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown() was not woken when the racing Get gave its object up")
+	}
+	if destroyed.Load() != 1 || pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("destroyed/live = %d/%d, want 1/0", destroyed.Load(), pool.CurrentPoolLength.Load())
+	}
+}
+
+// TestShutdownDeadline ensures Shutdown reports how many objects were still out when ctx expired.
+func TestShutdownDeadline(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o1, o2, idle := pool.Get(), pool.Get(), pool.Get()
+	pool.Put(idle)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = pool.Shutdown(ctx)
+	if !errors.Is(err, ErrObjectsOutstanding) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() error = %v, want ErrObjectsOutstanding and DeadlineExceeded", err)
+	}
+	if want := "objects still checked out: 2: context deadline exceeded"; !strings.Contains(err.Error(), want) {
+		t.Errorf("Shutdown() error = %q, want it to contain %q", err, want)
+	}
+	if shard := pool.Shards[0]; shard.Single.Load() != nil {
+		t.Error("Shutdown() should drain idle objects even when it times out")
+	}
+
+	pool.Put(o1)
+	pool.Put(o2)
+	if pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("CurrentPoolLength = %d, want 0 after late Puts", pool.CurrentPoolLength.Load())
+	}
+}
+
+// TestReshard grows and shrinks the shard set and checks idle objects follow the live shards.
+func TestReshard(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 2
+	cfg.Cleanup.Enabled = false
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	pool.reshard(4)
+	if n := len(pool.CurrentShards()); n != 4 {
+		t.Fatalf("len(CurrentShards()) = %d after growing, want 4", n)
+	}
+	if len(pool.Shards) != 2 {
+		t.Errorf("len(Shards) = %d, want the construction-time 2", len(pool.Shards))
+	}
+
+	for i := range 4 {
+		obj := testAllocator()
+		obj.SetShardIndex(i)
+		pool.CurrentShards()[i].push(obj)
+		pool.CurrentPoolLength.Add(1)
+	}
+	out := pool.Get()
+	out.SetShardIndex(3)
+	before := pool.Stats().Total
+
+	pool.reshard(2)
+	live := pool.CurrentShards()
+	if len(live) != 2 {
+		t.Fatalf("len(CurrentShards()) = %d after shrinking, want 2", len(live))
+	}
+	for i, shard := range live {
+		for _, obj := range shardObjects(shard) {
+			if obj.GetShardIndex() != i {
+				t.Errorf("object on shard %d has index %d", i, obj.GetShardIndex())
+			}
+		}
+	}
+	if idle := pool.IdleCount(); idle != 3 {
+		t.Errorf("idle = %d after shrinking, want 3", idle)
+	}
+	if total := pool.Stats().Total; total.Misses != before.Misses || total.Gets() != before.Gets() {
+		t.Errorf("Stats().Total changed by resharding: %+v, want %+v", total, before)
+	}
+
+	pool.Put(out)
+	if idx := out.GetShardIndex(); idx >= 2 {
+		t.Errorf("Put() left stale shard index %d", idx)
+	}
+	if idle := pool.IdleCount(); idle != 4 {
+		t.Errorf("idle = %d after Put, want 4", idle)
+	}
+
+	pool.reshard(3)
+	pool.Close()
+	if pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("CurrentPoolLength = %d after Close, want 0", pool.CurrentPoolLength.Load())
+	}
+}
+
+// shardObjects lists the idle objects of a shard without removing them.
+func shardObjects(shard *Shard[TestObject, *TestObject]) []*TestObject {
+	var objs []*TestObject
+	if obj := shard.Single.Load(); obj != nil {
+		objs = append(objs, obj)
+	}
+	for obj := shard.Head.Load(); obj != nil; obj = obj.GetNext() {
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+// TestReshardConcurrent reshards while workers Get and Put and checks nothing is lost.
+func TestReshardConcurrent(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 4
+	cfg.Cleanup.Enabled = false
+	cfg.Steal = StealPolicy{Enable: true, Batch: 3}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				// Hold two objects and return them to other shards so Gets go
+				// through the Head lists and steal batches from siblings.
+				a, b := pool.Get(), pool.Get()
+				runtime.Gosched()
+				a.SetShardIndex((w + i) % 5)
+				b.SetShardIndex((w + i + 1) % 5)
+				pool.Put(a)
+				pool.Put(b)
+			}
+		}()
+	}
+	for i := range 200 {
+		pool.reshard(5 - i%5) // ends on 1 shard, with the rest retired
+	}
+	close(stop)
+	wg.Wait()
+
+	if idle, live := pool.IdleCount(), pool.CurrentPoolLength.Load(); idle != live {
+		t.Errorf("idle = %d, live = %d; objects were lost during resharding", idle, live)
+	}
+	for _, shard := range pool.allShards[len(pool.shardList()):] {
+		if shard.Head.Load() != nil || shard.Single.Load() != nil || shard.idleLen() != 0 {
+			t.Errorf("retired shard %d still holds %d idle objects", shard.index, shard.idleLen())
+		}
+	}
+	pool.Close()
+	if pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("CurrentPoolLength = %d after Close, want 0", pool.CurrentPoolLength.Load())
+	}
+}
+
+// TestValidateConfigReshard checks that resharding needs automatic shard sizing and an interval.
+func TestValidateConfigReshard(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.Reshard = ReshardPolicy{Enable: true, Interval: time.Second}
+	cfg.NumShards = 2
+	if err := validateConfig(cfg); err == nil {
+		t.Error("validateConfig() should reject Reshard with a fixed NumShards")
+	}
+	cfg.NumShards = 0
+	cfg.Reshard.Interval = 0
+	if err := validateConfig(cfg); err == nil {
+		t.Error("validateConfig() should reject a zero reshard interval")
+	}
+	cfg.Reshard.Interval = time.Second
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Close()
+}
+
+// TestPrewarm checks round-robin distribution, shard indexes and hook/counter behavior.
+func TestPrewarm(t *testing.T) {
+	var allocated atomic.Int64
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 3
+	cfg.Cleanup.Enabled = false
+	cfg.Hooks.OnAllocate = func(*TestObject, int) { allocated.Add(1) }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	if n := pool.Prewarm(7); n != 7 {
+		t.Fatalf("Prewarm(7) = %d, want 7", n)
+	}
+	for i, want := range []int{3, 2, 2} {
+		objs := shardObjects(pool.Shards[i])
+		if len(objs) != want {
+			t.Errorf("shard %d holds %d objects, want %d", i, len(objs), want)
+		}
+		for _, obj := range objs {
+			if obj.GetShardIndex() != i {
+				t.Errorf("object on shard %d has index %d", i, obj.GetShardIndex())
+			}
+		}
+	}
+
+	stats := pool.Stats()
+	if allocated.Load() != 7 || stats.Live != 7 || stats.Idle != 7 || stats.Total.Misses != 0 {
+		t.Errorf("allocated/live/idle/misses = %d/%d/%d/%d, want 7/7/7/0",
+			allocated.Load(), stats.Live, stats.Idle, stats.Total.Misses)
+	}
+}
+
+// TestPrewarmCap ensures Prewarm stops at MaxPoolSize and InitialSize is validated.
+func TestPrewarmCap(t *testing.T) {
+	pool := newCappedPool(t, 4)
+	defer pool.Close()
+	if n := pool.Prewarm(10); n != 4 {
+		t.Errorf("Prewarm(10) = %d, want 4 with MaxPoolSize 4", n)
+	}
+	if n := pool.Prewarm(1); n != 0 {
+		t.Errorf("Prewarm(1) = %d at the cap, want 0", n)
+	}
+	if pool.CurrentPoolLength.Load() != 4 {
+		t.Errorf("CurrentPoolLength = %d, want 4", pool.CurrentPoolLength.Load())
+	}
+
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 2
+	cfg.InitialSize = 6
+	warm, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer warm.Close()
+	if idle := warm.Stats().Idle; idle != 6 {
+		t.Errorf("Idle = %d with InitialSize 6, want 6", idle)
+	}
+
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 5}
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject InitialSize above MaxPoolSize")
+	}
+	cfg.Growth = GrowthPolicy{}
+	cfg.InitialSize = -1
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject a negative InitialSize")
+	}
+}
+
+// TestCleanupMinIdle ensures cleanup keeps the most-used objects up to the idle floor.
+func TestCleanupMinIdle(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup = CleanupPolicy{Interval: time.Hour, MinUsageCount: 10, MinIdlePerShard: 2}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	objs := []*TestObject{pool.Get(), pool.Get(), pool.Get(), pool.Get(), pool.Get()}
+	for i, uses := range []int{1, 4, 2, 4, 3} {
+		objs[i].ResetUsage()
+		for range uses {
+			objs[i].IncrementUsage()
+		}
+	}
+	for _, obj := range objs {
+		pool.Put(obj)
+	}
+
+	pool.cleanupShard(pool.Shards[0])
+	kept := shardObjects(pool.Shards[0])
+	if len(kept) != 2 {
+		t.Fatalf("cleanup kept %d objects, want the floor of 2", len(kept))
+	}
+	for _, obj := range kept {
+		if obj != objs[1] && obj != objs[3] {
+			t.Error("cleanup should keep the most-used objects")
+		}
+	}
+	if live := pool.CurrentPoolLength.Load(); live != 2 {
+		t.Errorf("CurrentPoolLength = %d, want 2", live)
+	}
+
+	pool.cfg.Cleanup.MinIdlePerShard = 0
+	pool.cfg.Cleanup.MinIdle = 1
+	pool.cleanupShard(pool.Shards[0])
+	if n := len(shardObjects(pool.Shards[0])); n != 1 {
+		t.Errorf("cleanup kept %d objects with MinIdle 1, want 1", n)
+	}
+}
+
+// TestIdleFloor checks how the global MinIdle is spread over the shards.
+func TestIdleFloor(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 4
+	cfg.Cleanup.MinIdle = 5
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	if got := pool.idleFloor(); got != 2 {
+		t.Errorf("idleFloor() = %d with MinIdle 5 over 4 shards, want 2", got)
+	}
+	pool.cfg.Cleanup.MinIdlePerShard = 3
+	if got := pool.idleFloor(); got != 3 {
+		t.Errorf("idleFloor() = %d, want MinIdlePerShard 3", got)
+	}
+
+	cfg.Cleanup.MinIdle = -1
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject a negative MinIdle")
+	}
+}
+
+// TestCleanupTargetSize checks the fair per-shard quotas and that the most-used objects survive.
+func TestCleanupTargetSize(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 2
+	cfg.Cleanup = CleanupPolicy{Enabled: true, Interval: time.Hour, MinUsageCount: 1, TargetSize: 3}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	if q0, q1 := pool.shardQuota(pool.Shards[0]), pool.shardQuota(pool.Shards[1]); q0 != 2 || q1 != 1 {
+		t.Fatalf("shard quotas = %d/%d for TargetSize 3, want 2/1", q0, q1)
+	}
+
+	park := func(shardID int, usages ...int64) []*TestObject {
+		objs := make([]*TestObject, len(usages))
+		for i, uses := range usages {
+			objs[i] = testAllocator()
+			objs[i].usageCount.Store(uses)
+			objs[i].SetShardIndex(shardID)
+			pool.Shards[shardID].push(objs[i])
+			pool.CurrentPoolLength.Add(1)
+		}
+		return objs
+	}
+	first := park(0, 5, 2, 7, 3)
+	second := park(1, 1, 4, 2)
+
+	pool.cleanup()
+	assertKept := func(shardID int, want ...*TestObject) {
+		t.Helper()
+		kept := shardObjects(pool.Shards[shardID])
+		if len(kept) != len(want) {
+			t.Fatalf("shard %d kept %d objects, want %d", shardID, len(kept), len(want))
+		}
+		for _, obj := range kept {
+			if !slices.Contains(want, obj) {
+				t.Errorf("shard %d kept an object outside the most-used set", shardID)
+			}
+		}
+	}
+	assertKept(0, first[0], first[2])
+	assertKept(1, second[1])
+	if live := pool.CurrentPoolLength.Load(); live != 3 {
+		t.Errorf("CurrentPoolLength = %d, want TargetSize 3", live)
+	}
+
+	// A floor larger than the quota wins.
+	pool.cfg.Cleanup.TargetSize = 1
+	pool.cfg.Cleanup.MinIdlePerShard = 2
+	first[0].usageCount.Store(1)
+	first[2].usageCount.Store(1)
+	pool.cleanupShard(pool.Shards[0])
+	assertKept(0, first[0], first[2])
+}
+
+// TestCleanupMaxIdleTime ensures objects idle past MaxIdleTime are evicted even with MinUsageCount 0.
+func TestCleanupMaxIdleTime(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup = CleanupPolicy{Enabled: true, Interval: time.Hour, MaxIdleTime: time.Minute}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	stale, fresh := pool.Get(), pool.Get()
+	pool.Put(stale)
+	pool.Put(fresh)
+	if fresh.PoolMeta().state.lastUsed.Load() == 0 {
+		t.Fatal("Put() should record the last-used time when MaxIdleTime is set")
+	}
+	stale.PoolMeta().state.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+
+	pool.cleanup()
+	kept := shardObjects(pool.Shards[0])
+	if len(kept) != 1 || kept[0] != fresh {
+		t.Errorf("cleanup kept %d objects, want only the recently used one", len(kept))
+	}
+	if live := pool.CurrentPoolLength.Load(); live != 1 {
+		t.Errorf("CurrentPoolLength = %d, want 1", live)
+	}
+
+	if n := pool.Prewarm(1); n != 1 {
+		t.Fatalf("Prewarm(1) = %d, want 1", n)
+	}
+	pool.cleanup()
+	if n := len(shardObjects(pool.Shards[0])); n != 2 {
+		t.Errorf("cleanup kept %d objects, want 2: prewarmed objects start their idle clock", n)
+	}
+
+	cfg.Cleanup.MaxIdleTime = 0
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject MinUsageCount 0 without MaxIdleTime")
+	}
+}
+
+// TestMaxLifetime checks that Get, Put and cleanup retire objects past MaxLifetime.
+func TestMaxLifetime(t *testing.T) {
+	reasons := map[EvictReason]int{}
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup = CleanupPolicy{Enabled: true, Interval: time.Hour, MinUsageCount: 1, MinIdlePerShard: 4}
+	cfg.MaxLifetime = time.Hour
+	cfg.TrackStats = true
+	cfg.Hooks.OnEvict = func(_ *TestObject, _ int, reason EvictReason) { reasons[reason]++ }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	age := func(obj *TestObject) { obj.PoolMeta().state.createdAt = time.Now().Add(-2 * time.Hour).UnixNano() }
+
+	old := pool.Get()
+	if old.PoolMeta().state.createdAt == 0 {
+		t.Fatal("allocation should record the creation time when MaxLifetime is set")
+	}
+	pool.Put(old)
+	age(old)
+	if got := pool.Get(); got == old {
+		t.Error("Get() handed out an expired object")
+	} else {
+		age(got)
+		pool.Put(got)
+	}
+	if n := len(shardObjects(pool.Shards[0])); n != 0 {
+		t.Errorf("Put() pooled an expired object; shard holds %d", n)
+	}
+
+	idle := pool.Get()
+	pool.Put(idle)
+	age(idle)
+	pool.cleanup()
+	if n := len(shardObjects(pool.Shards[0])); n != 0 {
+		t.Error("cleanup should sweep expired objects even below the idle floor")
+	}
+
+	if reasons[EvictExpired] != 3 || pool.CurrentPoolLength.Load() != 0 {
+		t.Errorf("expired evictions/live = %d/%d, want 3/0", reasons[EvictExpired], pool.CurrentPoolLength.Load())
+	}
+	if ev := pool.Stats().Total.Evictions; ev != 3 {
+		t.Errorf("Stats().Total.Evictions = %d, want 3", ev)
+	}
+}
+
+// TestMaxLifetimeWakesWaiter ensures a Put that retires an expired object still serves a blocked GetContext.
+func TestMaxLifetimeWakesWaiter(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+	cfg.MaxLifetime = time.Hour
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	held := pool.Get()
+	got := make(chan *TestObject)
+	go func() {
+		obj, _ := pool.GetContext(context.Background())
+		got <- obj
+	}()
+	for pool.waiters.count.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	held.PoolMeta().state.createdAt = time.Now().Add(-2 * time.Hour).UnixNano()
+	pool.Put(held)
+	select {
+	case obj := <-got:
+		if obj == nil || obj == held {
+			t.Error("GetContext() should receive a fresh object after the expired one was retired")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetContext() was not woken after Put retired an expired object")
+	}
+	if live := pool.CurrentPoolLength.Load(); live != 1 {
+		t.Errorf("CurrentPoolLength = %d, want 1", live)
+	}
+}
+
+// TestReplenishTouches ensures an object allocated for a waiter gets its idle timestamp,
+// so idle-time cleanup does not treat it as idle since 1970.
+func TestReplenishTouches(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Cleanup.MaxIdleTime = time.Hour
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	held := pool.Get()
+	got := make(chan *TestObject)
+	go func() {
+		obj, _ := pool.GetContext(context.Background())
+		got <- obj
+	}()
+	for pool.waiters.count.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	before := time.Now().UnixNano()
+	pool.Discard(held)
+	select {
+	case obj := <-got:
+		if obj.PoolMeta().state.lastUsed.Load() < before {
+			t.Errorf("replenished object last used = %d, want at least %d", obj.PoolMeta().state.lastUsed.Load(), before)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetContext() was not woken by Discard")
+	}
+}
+
+// TestValidator ensures Get destroys objects the Validator rejects and moves on to the next one.
+func TestValidator(t *testing.T) {
+	var evicted []EvictReason
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Validator = func(obj *TestObject) bool { return obj.Value != "broken" }
+	cfg.Hooks.OnEvict = func(_ *TestObject, _ int, reason EvictReason) { evicted = append(evicted, reason) }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	good, bad := pool.Get(), pool.Get()
+	pool.Put(good) // Single
+	pool.Put(bad)  // Head
+	good.Value = "broken"
+
+	got := pool.Get()
+	if got != bad {
+		t.Fatal("Get() should skip the invalid object and return the next candidate")
+	}
+	if len(evicted) != 1 || evicted[0] != EvictInvalid {
+		t.Errorf("evictions = %v, want [invalid]", evicted)
+	}
+	if live := pool.CurrentPoolLength.Load(); live != 1 {
+		t.Errorf("CurrentPoolLength = %d, want 1 after discarding the invalid object", live)
+	}
+}
+
+// TestValidateAfter ensures only objects idle past the threshold are validated.
+func TestValidateAfter(t *testing.T) {
+	var calls atomic.Int64
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Validator = func(*TestObject) bool { calls.Add(1); return false }
+	cfg.ValidateAfter = time.Minute
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	obj := pool.Get()
+	pool.Put(obj)
+	if got := pool.Get(); got != obj || calls.Load() != 0 {
+		t.Errorf("recently returned object should be reused without validation (calls = %d)", calls.Load())
+	}
+
+	pool.Put(obj)
+	obj.PoolMeta().state.lastUsed.Store(time.Now().Add(-time.Hour).UnixNano())
+	if got := pool.Get(); got == obj || calls.Load() != 1 {
+		t.Errorf("stale object should be validated and discarded (calls = %d)", calls.Load())
+	}
+
+	cfg.Validator = nil
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject ValidateAfter without a Validator")
+	}
+}
+
+// TestLeakReport checks that debug mode reports objects never returned, with the stack of their Get.
+func TestLeakReport(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Debug = DebugOptions{TrackLeaks: true}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	returned := pool.Get()
+	leakyGet(pool)
+	pool.Put(returned)
+
+	leaks := pool.LeakReport()
+	if len(leaks) != 1 {
+		t.Fatalf("LeakReport() = %d leaks, want 1", len(leaks))
+	}
+	stack := leaks[0].Stack
+	if !strings.HasPrefix(stack, "github.com/AlexsanderHamir/GenPool/pool.leakyGet") {
+		t.Errorf("leak stack should start at the caller of Get:\n%s", stack)
+	}
+	if strings.Contains(stack, "ShardedPool") {
+		t.Errorf("leak stack should not include pool internals:\n%s", stack)
+	}
+
+	pool.cfg.Debug.LeakThreshold = time.Hour
+	if leaks := pool.LeakReport(); len(leaks) != 0 {
+		t.Errorf("LeakReport() = %d leaks below the threshold, want 0", len(leaks))
+	}
+
+	plain := newCappedPool(t, 1)
+	defer plain.Close()
+	_ = plain.Get()
+	if plain.LeakReport() != nil {
+		t.Error("LeakReport() should be nil without Debug.TrackLeaks")
+	}
+}
+
+// TestLeakStaleDrop ensures releasing the record of a collected checkout cannot remove
+// the record of a later checkout, even one of an object at the same address.
+func TestLeakStaleDrop(t *testing.T) {
+	leaks := newLeakTracker()
+	meta := &metaState{}
+	leaks.record(meta, 1)
+	stale := meta.leakID // as captured by the drop cleanup of the first checkout
+	leaks.forget(meta)
+	leaks.record(meta, 1) // the same memory checked out again
+
+	leaks.drop(stale)
+	if n := len(leaks.report(0)); n != 1 {
+		t.Errorf("report() = %d leaks after dropping a stale checkout, want 1", n)
+	}
+}
+
+// leakyGet takes an object and never returns it.
+func leakyGet(pool *ShardedPool[TestObject, *TestObject]) {
+	_ = pool.Get()
+}
+
+// TestCheckPuts ensures checked mode rejects double Puts and objects from another pool.
+func TestCheckPuts(t *testing.T) {
+	var violations []*Violation
+	newChecked := func(onViolation func(*Violation)) *ShardedPool[TestObject, *TestObject] {
+		cfg := DefaultConfig(testAllocator, testCleaner)
+		cfg.NumShards = 1
+		cfg.Cleanup.Enabled = false
+		cfg.Debug = DebugOptions{CheckPuts: true, OnViolation: onViolation}
+		pool, err := NewPoolWithConfig(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(pool.Close)
+		return pool
+	}
+	pool := newChecked(func(v *Violation) { violations = append(violations, v) })
+	other := newChecked(nil)
+
+	obj := pool.Get()
+	pool.Put(obj)
+	pool.Put(obj)
+	if len(violations) != 1 || violations[0].Kind != ViolationDoublePut {
+		t.Fatalf("violations = %v, want one double put", violations)
+	}
+	if !strings.HasPrefix(violations[0].Stack, "github.com/AlexsanderHamir/GenPool/pool.TestCheckPuts") {
+		t.Errorf("violation stack should start at the caller of Put:\n%s", violations[0].Stack)
+	}
+	if n := len(shardObjects(pool.Shards[0])); n != 1 {
+		t.Errorf("shard holds %d objects after a double Put, want 1", n)
+	}
+
+	if got := pool.Get(); got != obj {
+		t.Fatal("Get() should reuse the pooled object")
+	}
+	pool.Put(obj)
+	if len(violations) != 1 {
+		t.Errorf("a Put after Get should not be reported; got %d violations", len(violations))
+	}
+
+	foreign := other.Get()
+	pool.Put(foreign)
+	if len(violations) != 2 || violations[1].Kind != ViolationForeignObject {
+		t.Errorf("violations = %v, want a foreign object report", violations)
+	}
+
+	defer func() {
+		v, ok := recover().(*Violation)
+		if !ok || v.Kind != ViolationDoublePut {
+			t.Errorf("Put() without OnViolation should panic with a double put violation, got %v", v)
+		}
+	}()
+	other.Put(foreign)
+	other.Put(foreign)
+}
+
+// poisonObject has one field of each kind the default poisoning handles.
+type poisonObject struct {
+	N     int
+	label string
+	Buf   []byte
+	Ratio float32
+	Inner struct {
+		A uint8
+		B [2]int16
+	}
+	Fields[poisonObject]
+}
+
+// handObject implements Poolable by hand instead of embedding Fields.
+type handObject struct {
+	N     int
+	next  *handObject
+	usage int64
+	shard int
+	meta  Meta
+}
+
+func (h *handObject) GetNext() *handObject     { return h.next }
+func (h *handObject) SetNext(next *handObject) { h.next = next }
+func (h *handObject) GetUsageCount() int64     { return h.usage }
+func (h *handObject) IncrementUsage()          { h.usage++ }
+func (h *handObject) ResetUsage()              { h.usage = 0 }
+func (h *handObject) SetShardIndex(index int)  { h.shard = index }
+func (h *handObject) GetShardIndex() int       { return h.shard }
+func (h *handObject) PoolMeta() *Meta          { return &h.meta }
+
+func handConfig() Config[handObject, *handObject] {
+	return Config[handObject, *handObject]{
+		NumShards: 1,
+		Allocator: func() *handObject { return &handObject{} },
+		Cleaner:   func(obj *handObject) { obj.N = 0 },
+	}
+}
+
+func newPoisonPool(t *testing.T, cfg Config[poisonObject, *poisonObject]) *ShardedPool[poisonObject, *poisonObject] {
+	t.Helper()
+	cfg.NumShards = 1
+	cfg.Allocator = func() *poisonObject { return &poisonObject{Buf: make([]byte, 0, 8)} }
+	cfg.Cleaner = func(obj *poisonObject) {
+		obj.N, obj.label, obj.Buf, obj.Ratio = 0, "", obj.Buf[:0], 0
+	}
+	cfg.Debug.Poison = true
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+// TestPoison checks that idle objects are poisoned, writes while idle are reported with
+// their field paths, and Get and the Destroyer see the clean state.
+func TestPoison(t *testing.T) {
+	var violations []*Violation
+	var destroyedBuf []byte
+	pool := newPoisonPool(t, Config[poisonObject, *poisonObject]{
+		Debug:     DebugOptions{OnViolation: func(v *Violation) { violations = append(violations, v) }},
+		Destroyer: func(obj *poisonObject) { destroyedBuf = obj.Buf },
+	})
+
+	obj := pool.Get()
+	obj.N, obj.label = 42, "req-1"
+	pool.Put(obj)
+	if obj.Buf != nil || obj.label != poisonString || obj.N == 0 || obj.Inner.B[1] == 0 {
+		t.Fatalf("Put() did not poison the object: N=%d label=%q Buf=%v", obj.N, obj.label, obj.Buf)
+	}
+
+	obj.N = 7
+	obj.Inner.B[1] = 1
+	got := pool.Get()
+	if got != obj {
+		t.Fatal("Get() should reuse the pooled object")
+	}
+	if len(violations) != 1 || violations[0].Kind != ViolationUseAfterPut {
+		t.Fatalf("violations = %v, want one use after put", violations)
+	}
+	if want := []string{"N", "Inner.B[1]"}; !slices.Equal(violations[0].Fields, want) {
+		t.Errorf("violation fields = %v, want %v", violations[0].Fields, want)
+	}
+	if got.N != 0 || got.label != "" || got.Buf == nil || cap(got.Buf) != 8 || got.Inner.B[1] != 0 {
+		t.Errorf("Get() should restore the clean state, got N=%d label=%q Buf=%v", got.N, got.label, got.Buf)
+	}
+
+	pool.Put(got)
+	pool.Close()
+	if len(violations) != 1 {
+		t.Errorf("an untouched idle object should not be reported; got %d violations", len(violations))
+	}
+	if destroyedBuf == nil {
+		t.Error("the Destroyer should see the clean state, not the poison")
+	}
+}
+
+// TestPoisoner ensures a custom Poisoner replaces the reflection-based pattern.
+func TestPoisoner(t *testing.T) {
+	var violations []*Violation
+	pool := newPoisonPool(t, Config[poisonObject, *poisonObject]{
+		Debug:    DebugOptions{OnViolation: func(v *Violation) { violations = append(violations, v) }},
+		Poisoner: func(obj *poisonObject) { obj.N = -1 },
+	})
+	defer pool.Close()
+
+	obj := pool.Get()
+	pool.Put(obj)
+	if obj.N != -1 || obj.Buf == nil {
+		t.Fatalf("Put() should run only the custom Poisoner, got N=%d Buf=%v", obj.N, obj.Buf)
+	}
+	obj.label = "late write"
+	pool.Get()
+	if len(violations) != 1 || !slices.Equal(violations[0].Fields, []string{"label"}) {
+		t.Errorf("violations = %v, want one on label", violations)
+	}
+
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.Poisoner = func(*TestObject) {}
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject a Poisoner without Debug.Poison")
+	}
+}
+
+// TestCheckCleanerHandImplemented ensures CheckCleaner is rejected for a hand-implemented
+// Poolable, whose pool-owned fields would differ from the reference after every Get.
+func TestCheckCleanerHandImplemented(t *testing.T) {
+	cfg := handConfig()
+	cfg.Debug.CheckCleaner = true
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject Debug.CheckCleaner for a type without Fields")
+	}
+}
+
+// TestPoisonHandImplemented ensures Poison is rejected for a hand-implemented Poolable,
+// whose list, usage and shard fields would otherwise be poisoned, while the type still
+// pools normally without it.
+func TestPoisonHandImplemented(t *testing.T) {
+	cfg := handConfig()
+	cfg.Debug.Poison = true
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject Debug.Poison for a type without Fields")
+	}
+
+	cfg = handConfig()
+	cfg.MaxLifetime = time.Hour // allocates Meta
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	obj := pool.Get()
+	obj.N = 42
+	pool.Put(obj)
+	if got := pool.Get(); got != obj || got.N != 0 || got.GetUsageCount() != 2 {
+		t.Errorf("Get() = %p (N=%d, usage=%d), want %p cleaned with usage 2", got, got.N, got.GetUsageCount(), obj)
+	}
+}
+
+// TestCheckCleaner ensures fields the Cleaner forgets are reported with their paths.
+func TestCheckCleaner(t *testing.T) {
+	var violations []*Violation
+	var destroyed atomic.Int64
+	cfg := Config[poisonObject, *poisonObject]{
+		NumShards: 1,
+		Allocator: func() *poisonObject { return &poisonObject{Buf: make([]byte, 0, 8)} },
+		Cleaner:   func(obj *poisonObject) { obj.N, obj.Buf = 0, obj.Buf[:0] }, // forgets label and Inner
+		Destroyer: func(*poisonObject) { destroyed.Add(1) },
+		Debug: DebugOptions{
+			CheckCleaner: true,
+			OnViolation:  func(v *Violation) { violations = append(violations, v) },
+		},
+	}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := pool.Get()
+	obj.N, obj.Buf = 3, append(obj.Buf, 'x')
+	pool.Put(obj)
+	if len(violations) != 0 {
+		t.Fatalf("a properly cleaned object was reported: %v", violations[0])
+	}
+
+	obj = pool.Get()
+	obj.label, obj.Inner.A = "req-2", 9
+	pool.Put(obj)
+	if len(violations) != 1 || violations[0].Kind != ViolationDirtyObject {
+		t.Fatalf("violations = %v, want one dirty object", violations)
+	}
+	if want := []string{"label", "Inner.A"}; !slices.Equal(violations[0].Fields, want) {
+		t.Errorf("violation fields = %v, want %v", violations[0].Fields, want)
+	}
+
+	pool.Close()
+	if destroyed.Load() != 2 {
+		t.Errorf("destroyed = %d, want 2 (the pooled object and the reference)", destroyed.Load())
+	}
+}
+
+// TestDiscard ensures Discard destroys the object, frees its budget and serves a blocked GetContext.
+func TestDiscard(t *testing.T) {
+	var evicted []EvictReason
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+	cfg.Hooks.OnEvict = func(_ *TestObject, _ int, reason EvictReason) { evicted = append(evicted, reason) }
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	held := pool.Get()
+	got := make(chan *TestObject)
+	go func() {
+		obj, _ := pool.GetContext(context.Background())
+		got <- obj
+	}()
+	for pool.waiters.count.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	pool.Discard(held)
+	select {
+	case obj := <-got:
+		if obj == nil || obj == held {
+			t.Error("GetContext() should receive a fresh object after Discard")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetContext() was not woken by Discard")
+	}
+	if len(evicted) != 1 || evicted[0] != EvictDiscard {
+		t.Errorf("evictions = %v, want [discard]", evicted)
+	}
+	if stats := pool.Stats(); stats.Discarded != 1 || stats.Live != 1 {
+		t.Errorf("Discarded/Live = %d/%d, want 1/1", stats.Discarded, stats.Live)
+	}
+}
+
+// TestRecoverDropped ensures the budget of an object dropped without Put comes back after GC.
+func TestRecoverDropped(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 1
+	cfg.Cleanup.Enabled = false
+	cfg.Growth = GrowthPolicy{Enable: true, MaxPoolSize: 1}
+	cfg.RecoverDropped = true
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	// A returned object must not be reported as dropped once it is destroyed.
+	returned := pool.Get()
+	pool.Put(returned)
+	pool.clear()
+	runtime.GC()
+
+	dropObject(pool)
+	if pool.Get() != nil {
+		t.Fatal("Get() should hit the cap while the dropped object is still accounted for")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.CurrentPoolLength.Load() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("dropped object was never recovered")
+		}
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+
+	if obj := pool.Get(); obj == nil {
+		t.Error("Get() should allocate once the dropped object's budget is released")
+	}
+	if dropped := pool.Stats().Dropped; dropped != 1 {
+		t.Errorf("Stats().Dropped = %d, want 1", dropped)
+	}
+}
+
+// dropObject takes an object and loses it without Put.
+//
+//go:noinline
+func dropObject(pool *ShardedPool[TestObject, *TestObject]) {
+	_ = pool.Get()
+}
+
+// TestCounts checks the idle, in-use and total counters across Get, Put and Discard.
+func TestCounts(t *testing.T) {
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 2
+	cfg.Cleanup.Enabled = false
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	assertCounts := func(idle, inUse, total int64) {
+		t.Helper()
+		if got := pool.IdleCount(); got != idle {
+			t.Errorf("IdleCount() = %d, want %d", got, idle)
+		}
+		if got := pool.InUseCount(); got != inUse {
+			t.Errorf("InUseCount() = %d, want %d", got, inUse)
+		}
+		if got := pool.TotalCount(); got != total {
+			t.Errorf("TotalCount() = %d, want %d", got, total)
+		}
+	}
+
+	a, b, c := pool.Get(), pool.Get(), pool.Get()
+	assertCounts(0, 3, 3)
+	pool.Put(a)
+	assertCounts(1, 2, 3)
+	pool.Discard(b)
+	assertCounts(1, 1, 2)
+	if n := pool.Prewarm(2); n != 2 {
+		t.Fatalf("Prewarm(2) = %d, want 2", n)
+	}
+	assertCounts(3, 1, 4)
+
+	pool.reshard(1)
+	pool.Put(c)
+	assertCounts(4, 0, 4)
+	if stats := pool.Stats(); stats.InUse != 0 || stats.Idle != 4 || stats.Live != 4 {
+		t.Errorf("Stats() idle/in use/live = %d/%d/%d, want 4/0/4", stats.Idle, stats.InUse, stats.Live)
+	}
+}
+
+// TestMaxIdle ensures Put destroys objects beyond GrowthPolicy.MaxIdle and Prewarm stops at it.
+func TestMaxIdle(t *testing.T) {
+	var surplus atomic.Int64
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 2
+	cfg.Cleanup.Enabled = false
+	cfg.Growth.MaxIdle = 2
+	cfg.Hooks.OnEvict = func(_ *TestObject, _ int, reason EvictReason) {
+		if reason == EvictSurplus {
+			surplus.Add(1)
+		}
+	}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	objs := []*TestObject{pool.Get(), pool.Get(), pool.Get(), pool.Get()}
+	for i, obj := range objs {
+		obj.SetShardIndex(i % 2)
+		pool.Put(obj)
+	}
+	if idle, total := pool.IdleCount(), pool.TotalCount(); idle != 2 || total != 2 {
+		t.Errorf("idle/total = %d/%d after four Puts, want 2/2", idle, total)
+	}
+	if surplus.Load() != 2 {
+		t.Errorf("surplus evictions = %d, want 2", surplus.Load())
+	}
+	if n := pool.Prewarm(5); n != 0 {
+		t.Errorf("Prewarm(5) = %d with the idle cap reached, want 0", n)
+	}
+
+	cfg.Growth.MaxIdle = -1
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject a negative MaxIdle")
+	}
+}
+
+// TestMaxIdleBelowShards ensures a MaxIdle smaller than the shard count still lets any
+// one shard pool objects while the pool as a whole has room.
+func TestMaxIdleBelowShards(t *testing.T) {
+	var surplus atomic.Int64
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 8
+	cfg.Cleanup.Enabled = false
+	cfg.Growth.MaxIdle = 4
+	cfg.Hooks.OnEvict = func(_ *TestObject, _ int, reason EvictReason) {
+		if reason == EvictSurplus {
+			surplus.Add(1)
+		}
+	}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	for range 5 {
+		obj := pool.Get()
+		obj.SetShardIndex(7)
+		pool.Put(obj)
+	}
+	if idle := pool.Shards[7].idleLen(); idle != 4 {
+		t.Errorf("shard[7] idle = %d, want 4", idle)
+	}
+	if surplus.Load() != 1 {
+		t.Errorf("surplus evictions = %d, want 1", surplus.Load())
+	}
+	if n := pool.Prewarm(5); n != 0 {
+		t.Errorf("Prewarm(5) = %d with the idle cap reached, want 0", n)
+	}
+}
+
+// TestMaxIdlePerShard ensures Put destroys objects beyond a shard's idle cap while other
+// shards keep accepting them, and that Prewarm fills every shard up to the cap.
+func TestMaxIdlePerShard(t *testing.T) {
+	var surplus atomic.Int64
+	cfg := DefaultConfig(testAllocator, testCleaner)
+	cfg.NumShards = 2
+	cfg.Cleanup.Enabled = false
+	cfg.Growth.MaxIdlePerShard = 2
+	cfg.Hooks.OnEvict = func(_ *TestObject, _ int, reason EvictReason) {
+		if reason == EvictSurplus {
+			surplus.Add(1)
+		}
+	}
+	pool, err := NewPoolWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	var objs []*TestObject
+	for range 4 {
+		obj := pool.Get()
+		obj.SetShardIndex(0)
+		objs = append(objs, obj)
+	}
+	for _, obj := range objs {
+		pool.Put(obj)
+	}
+	shards := pool.shardList()
+	if idle := shards[0].idleLen(); idle != 2 {
+		t.Errorf("shard 0 idle = %d, want 2", idle)
+	}
+	if surplus.Load() != 2 {
+		t.Errorf("surplus evictions = %d, want 2", surplus.Load())
+	}
+
+	if n := pool.Prewarm(5); n != 2 {
+		t.Errorf("Prewarm(5) = %d, want 2 (shard 1 only)", n)
+	}
+	for i, shard := range shards {
+		if idle := shard.idleLen(); idle != 2 {
+			t.Errorf("shard %d idle = %d after Prewarm, want 2", i, idle)
+		}
+	}
+	if total := pool.TotalCount(); total != 4 {
+		t.Errorf("TotalCount() = %d, want 4", total)
+	}
+
+	cfg.Growth.MaxIdlePerShard = -1
+	if _, err := NewPoolWithConfig(cfg); err == nil {
+		t.Error("NewPoolWithConfig() should reject a negative MaxIdlePerShard")
+	}
 }
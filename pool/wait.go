@@ -0,0 +1,176 @@
+// Blocking acquisition. GetContext parks callers when GrowthPolicy.MaxPoolSize is
+// reached and nothing is idle; Put hands returned objects straight to parked callers
+// instead of pooling them, and paths that free budget allocate one for them.
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// waiter is a parked GetContext caller; ch is buffered so Put never blocks on handoff.
+type waiter[T any] struct {
+	ch chan *T
+}
+
+// waitQueue is a FIFO of parked callers: GetContext callers waiting for an object, or
+// Shutdown calls waiting for the in-use count to drop. count lets Put skip the lock
+// when nobody is waiting.
+type waitQueue[T any] struct {
+	mu      sync.Mutex
+	waiters []*waiter[T]
+	count   atomic.Int64
+}
+
+func (q *waitQueue[T]) enqueue() *waiter[T] {
+	w := &waiter[T]{ch: make(chan *T, 1)}
+	q.mu.Lock()
+	q.waiters = append(q.waiters, w)
+	q.count.Add(1)
+	q.mu.Unlock()
+	return w
+}
+
+// remove takes w out of the queue. It returns false if w was already dequeued by
+// handoff, in which case an object is (or will be) in w.ch.
+func (q *waitQueue[T]) remove(w *waiter[T]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, cur := range q.waiters {
+		if cur == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			q.count.Add(-1)
+			return true
+		}
+	}
+	return false
+}
+
+// handoff gives obj to the oldest waiter. It returns false if nobody is waiting.
+func (q *waitQueue[T]) handoff(obj *T) bool {
+	return q.count.Load() > 0 && q.handoffLocked(obj)
+}
+
+func (q *waitQueue[T]) handoffLocked(obj *T) bool {
+	q.mu.Lock()
+	if len(q.waiters) == 0 {
+		q.mu.Unlock()
+		return false
+	}
+	w := q.waiters[0]
+	q.waiters[0] = nil
+	q.waiters = q.waiters[1:]
+	q.count.Add(-1)
+	q.mu.Unlock()
+
+	w.ch <- obj
+	return true
+}
+
+// closeAll wakes every waiter with nil, which GetContext reports as ErrPoolClosed and
+// Shutdown takes as a cue to count again.
+func (q *waitQueue[T]) closeAll() {
+	q.mu.Lock()
+	waiters := q.waiters
+	q.waiters = nil
+	q.count.Add(-int64(len(waiters)))
+	q.mu.Unlock()
+
+	for _, w := range waiters {
+		w.ch <- nil
+	}
+}
+
+// GetContext is like Get but, when GrowthPolicy.MaxPoolSize is reached and no reusable
+// object is available in any shard, it blocks until an object is returned or its budget
+// is freed, or ctx is done. It returns ctx.Err() if ctx ends first and ErrPoolClosed if
+// the pool is closed while waiting. Without a growth policy it never blocks.
+func (p *ShardedPool[T, P]) GetContext(ctx context.Context) (P, error) {
+	obj, err := p.tryAll()
+	if !errors.Is(err, ErrPoolExhausted) {
+		return obj, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for {
+		w := p.waiters.enqueue()
+
+		// An object may have been pooled or freed (or Close may have run) between the
+		// failed attempt and enqueue; look again. Anything pushed after this point is
+		// handed over by feed, and budget freed after it by replenish.
+		if obj, err = p.tryAll(); !errors.Is(err, ErrPoolExhausted) {
+			if !p.waiters.remove(w) {
+				p.giveBack(<-w.ch)
+			}
+			return obj, err
+		}
+
+		select {
+		case handed := <-w.ch:
+			if handed == nil {
+				return nil, ErrPoolClosed
+			}
+			// Handed-over objects skip popIdle, so vet them here. Retiring one frees its
+			// budget, which the next tryAll picks up.
+			p.unpoison(handed)
+			if reason, bad := p.unusable(handed); bad {
+				p.retire(handed, reason)
+				continue
+			}
+			if obj = p.handOut(handed); obj == nil {
+				return nil, ErrPoolClosed
+			}
+			return obj, nil
+		case <-ctx.Done():
+			if !p.waiters.remove(w) {
+				p.giveBack(<-w.ch)
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// tryAll is TryGet followed by a scan of every live shard, so a caller does not park
+// while objects sit idle in shards other than its own.
+func (p *ShardedPool[T, P]) tryAll() (P, error) {
+	obj, err := p.TryGet()
+	if !errors.Is(err, ErrPoolExhausted) {
+		return obj, err
+	}
+	for _, shard := range p.shardList() {
+		if obj = p.popIdle(shard); obj != nil {
+			if obj = p.handOut(obj); obj == nil {
+				return nil, ErrPoolClosed
+			}
+			return obj, nil
+		}
+	}
+	return nil, ErrPoolExhausted
+}
+
+// feed hands objects idle in shard to parked GetContext callers. Every path that
+// pushes into a shard calls it afterwards, through settle: a caller that enqueued
+// before the push may already have scanned past the shard.
+func (p *ShardedPool[T, P]) feed(shard *Shard[T, P]) {
+	for p.waiters.count.Load() > 0 {
+		obj, _ := shard.pop()
+		if obj == nil {
+			return
+		}
+		if !p.waiters.handoff(obj) {
+			shard.push(obj) // the waiters left meanwhile; check again for new ones
+		}
+	}
+}
+
+// giveBack returns an object handed to a waiter that no longer wants it. nil means
+// the waiter was released by Close and there is nothing to return.
+func (p *ShardedPool[T, P]) giveBack(obj *T) {
+	if obj != nil {
+		p.recycle(obj)
+	}
+}
@@ -79,10 +79,15 @@ func resetLikeCleaner(obj *BenchmarkObject) {
 }
 
 func newGenPoolForBench(b *testing.B) (*pool.ShardedPool[BenchmarkObject, *BenchmarkObject], func()) {
+	return newGenPoolWithStats(b, false)
+}
+
+func newGenPoolWithStats(b *testing.B, trackStats bool) (*pool.ShardedPool[BenchmarkObject, *BenchmarkObject], func()) {
 	b.Helper()
 	cfg := pool.Config[BenchmarkObject, *BenchmarkObject]{
-		Allocator: benchAllocator,
-		Cleaner:   benchCleaner,
+		Allocator:  benchAllocator,
+		Cleaner:    benchCleaner,
+		TrackStats: trackStats,
 	}
 	p, err := pool.NewPoolWithConfig(cfg)
 	if err != nil {
@@ -114,6 +119,22 @@ func BenchmarkGenPool(b *testing.B) {
 	}
 }
 
+// BenchmarkGenPoolTracked is the pool_only scenario with Config.TrackStats set, to
+// show what the activity counters cost.
+func BenchmarkGenPoolTracked(b *testing.B) {
+	p, cleanup := newGenPoolWithStats(b, true)
+	defer cleanup()
+
+	b.SetParallelism(benchParallelism)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			obj := p.Get()
+			p.Put(obj)
+		}
+	})
+}
+
 func BenchmarkSyncPool(b *testing.B) {
 	for _, sc := range benchScenarios {
 		b.Run(sc.name, func(b *testing.B) {
@@ -132,3 +153,29 @@ func BenchmarkSyncPool(b *testing.B) {
 		})
 	}
 }
+
+// headBatch is how many objects BenchmarkGenPoolHead holds at once; all but one per
+// shard go through the Head list rather than the Single slot.
+const headBatch = 4
+
+// BenchmarkGenPoolHead takes and returns objects in batches so Get pops from the Head
+// list, which serializes pops per shard to stay ABA-safe. One op is a batch of
+// headBatch Gets followed by as many Puts.
+func BenchmarkGenPoolHead(b *testing.B) {
+	p, cleanup := newGenPoolForBench(b)
+	defer cleanup()
+
+	b.SetParallelism(benchParallelism)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var held [headBatch]*BenchmarkObject
+		for pb.Next() {
+			for i := range held {
+				held[i] = p.Get()
+			}
+			for _, obj := range held {
+				p.Put(obj)
+			}
+		}
+	})
+}